@@ -8,8 +8,10 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Broker imports load IANA timezones
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -17,8 +19,12 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/tradepulse/api/internal/database"
 	"github.com/tradepulse/api/internal/handlers"
+	"github.com/tradepulse/api/internal/integrations"
+	"github.com/tradepulse/api/internal/jobs"
 	appMiddleware "github.com/tradepulse/api/internal/middleware"
+	"github.com/tradepulse/api/internal/models"
 	"github.com/tradepulse/api/internal/notifications"
+	"github.com/tradepulse/api/internal/secrets"
 )
 
 type application struct {
@@ -26,6 +32,9 @@ type application struct {
 	logger          *slog.Logger
 	config          config
 	notificationBus *notifications.Bus
+	jobRunner       *jobs.Runner
+	cipher          *secrets.Cipher
+	brokers         *integrations.Registry
 }
 
 type config struct {
@@ -34,6 +43,9 @@ type config struct {
 	allowedOrigins string
 	jwtSecret      string
 	jwtExpiry      string
+	jobWorkers     int
+	credentialsKey string
+	propReports    integrations.PropReportsConfig
 }
 
 func main() {
@@ -54,6 +66,24 @@ func main() {
 		allowedOrigins: getEnv("ALLOWED_ORIGINS", "https://tradepulse.drivenw.com"),
 		jwtSecret:      getEnv("JWT_SECRET", ""),
 		jwtExpiry:      getEnv("JWT_EXPIRY", "24h"),
+		credentialsKey: getEnv("CREDENTIALS_KEY", ""),
+	}
+
+	jobWorkers, err := strconv.Atoi(getEnv("JOB_WORKERS", "2"))
+	if err != nil {
+		logger.Error("JOB_WORKERS must be a number", "error", err)
+		os.Exit(1)
+	}
+	cfg.jobWorkers = jobWorkers
+
+	cfg.propReports = integrations.DefaultPropReportsConfig
+	if cfg.propReports.RequestsPerSecond, err = strconv.ParseFloat(getEnv("PROPREPORTS_RATE_LIMIT", "2"), 64); err != nil {
+		logger.Error("PROPREPORTS_RATE_LIMIT must be a number", "error", err)
+		os.Exit(1)
+	}
+	if cfg.propReports.MaxRetries, err = strconv.Atoi(getEnv("PROPREPORTS_MAX_RETRIES", "3")); err != nil {
+		logger.Error("PROPREPORTS_MAX_RETRIES must be a number", "error", err)
+		os.Exit(1)
 	}
 
 	if cfg.jwtSecret == "" {
@@ -61,6 +91,18 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Saved broker connections need a key to encrypt their credentials
+	var cipher *secrets.Cipher
+	if cfg.credentialsKey != "" {
+		cipher, err = secrets.NewCipher(cfg.credentialsKey)
+		if err != nil {
+			logger.Error("Invalid CREDENTIALS_KEY", "error", err)
+			os.Exit(1)
+		}
+	} else {
+		logger.Warn("CREDENTIALS_KEY not set, saved broker connections are disabled")
+	}
+
 	// Initialize database
 	db, err := database.New(database.Config{
 		Host:     getEnv("DB_HOST", "postgres1.drivenw.local"),
@@ -93,12 +135,34 @@ func main() {
 
 	logger.Info("Notification bus started")
 
+	// Broker integrations available for fetches and saved connections
+	brokers := integrations.NewRegistry(
+		integrations.NewPropReports(cfg.propReports),
+	)
+
+	// Start background job workers
+	jobRunner := jobs.NewRunner(db, notificationBus, logger, cfg.jobWorkers)
+	jobRunner.Register(models.JobTypeBrokerImport, jobs.BrokerImport(db, cipher, brokers))
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobsDone := make(chan struct{})
+	go func() {
+		jobRunner.Run(jobsCtx)
+		close(jobsDone)
+	}()
+
+	// Queue scheduled broker syncs
+	go jobs.NewScheduler(db, jobRunner, logger).Run(jobsCtx)
+
 	// Initialize application
 	app := &application{
 		db:              db,
 		logger:          logger,
 		config:          cfg,
 		notificationBus: notificationBus,
+		jobRunner:       jobRunner,
+		cipher:          cipher,
+		brokers:         brokers,
 	}
 
 	// Setup router
@@ -134,7 +198,12 @@ func main() {
 	// Initialize handlers
 	tradesHandler := handlers.NewTradesHandler(app.db, app.notificationBus)
 	tagsHandler := handlers.NewTagsHandler(app.db)
+	accountsHandler := handlers.NewAccountsHandler(app.db)
+	cashTransactionsHandler := handlers.NewCashTransactionsHandler(app.db)
 	csvImportHandler := handlers.NewCSVImportHandler(app.db, app.notificationBus)
+	importProfilesHandler := handlers.NewImportProfilesHandler(app.db)
+	importBatchesHandler := handlers.NewImportBatchesHandler(app.db, app.notificationBus)
+	connectionsHandler := handlers.NewIntegrationConnectionsHandler(app.db, app.cipher, app.jobRunner, app.brokers)
 
 	// API routes
 	r.Route("/api", func(r chi.Router) {
@@ -162,12 +231,51 @@ func main() {
 			r.Get("/trades/{id}", tradesHandler.GetTrade)
 			r.Put("/trades/{id}", tradesHandler.UpdateTrade)
 			r.Delete("/trades/{id}", tradesHandler.DeleteTrade)
+			r.Get("/trades/{id}/executions", tradesHandler.ListTradeExecutions)
+			r.Post("/trades/{id}/rebuild", tradesHandler.RebuildTrade)
 			r.Post("/trades/import-csv", csvImportHandler.ImportCSV)
+			r.Post("/trades/import/das", csvImportHandler.ImportDAS)
+			r.Post("/trades/import/ibkr", csvImportHandler.ImportIBKR)
+			r.Post("/trades/import/thinkorswim", csvImportHandler.ImportTOS)
+			r.Post("/trades/import/ninjatrader", csvImportHandler.ImportNinjaTrader)
+			r.Post("/trades/import/tradovate", csvImportHandler.ImportTradovate)
+			r.Post("/trades/import/metatrader", csvImportHandler.ImportMetaTrader)
+			r.Post("/trades/import/ofx", csvImportHandler.ImportOFX)
+			r.Post("/trades/import/upload", csvImportHandler.UploadCSV)
 
 			// Trade tags
 			r.Post("/trades/{id}/tags", tradesHandler.AddTagToTrade)
 			r.Delete("/trades/{tradeId}/tags/{tagId}", tradesHandler.RemoveTagFromTrade)
 
+			// Accounts
+			r.Get("/accounts", accountsHandler.ListAccounts)
+			r.Post("/accounts", accountsHandler.CreateAccount)
+			r.Get("/accounts/{id}", accountsHandler.GetAccount)
+			r.Put("/accounts/{id}", accountsHandler.UpdateAccount)
+			r.Delete("/accounts/{id}", accountsHandler.DeleteAccount)
+			r.Get("/accounts/{id}/balances", accountsHandler.GetAccountBalances)
+
+			// Cash ledger
+			r.Get("/cash-transactions", cashTransactionsHandler.ListCashTransactions)
+			r.Post("/cash-transactions", cashTransactionsHandler.CreateCashTransaction)
+			r.Get("/cash-transactions/{id}", cashTransactionsHandler.GetCashTransaction)
+			r.Put("/cash-transactions/{id}", cashTransactionsHandler.UpdateCashTransaction)
+			r.Delete("/cash-transactions/{id}", cashTransactionsHandler.DeleteCashTransaction)
+
+			// Import profiles
+			r.Get("/import-profiles", importProfilesHandler.ListImportProfiles)
+			r.Post("/import-profiles", importProfilesHandler.CreateImportProfile)
+			r.Get("/import-profiles/{id}", importProfilesHandler.GetImportProfile)
+			r.Put("/import-profiles/{id}", importProfilesHandler.UpdateImportProfile)
+			r.Delete("/import-profiles/{id}", importProfilesHandler.DeleteImportProfile)
+
+			// Import batches
+			r.Post("/imports/preview", csvImportHandler.PreviewImport)
+			r.Post("/imports/commit", csvImportHandler.CommitImport)
+			r.Get("/imports", importBatchesHandler.ListImportBatches)
+			r.Get("/imports/{id}", importBatchesHandler.GetImportBatch)
+			r.Post("/imports/{id}/rollback", importBatchesHandler.RollbackImportBatch)
+
 			// Journal
 			r.Get("/journal", handlers.ListJournalEntries(app.db, app.logger))
 			r.Post("/journal", handlers.CreateJournalEntry(app.db, app.logger))
@@ -214,7 +322,20 @@ func main() {
 			r.Get("/notifications/stats", handlers.HandleNotificationStats(app.notificationBus, app.logger))
 
 			// Integrations
-			r.Post("/integrations/propreports/fetch", handlers.FetchPropReportsTrades(app.logger))
+			r.Get("/integrations", handlers.ListIntegrations(app.brokers))
+			r.Post("/integrations/{provider}/test", handlers.TestBrokerCredentials(app.brokers, app.logger))
+			r.Post("/integrations/{provider}/fetch", handlers.FetchBrokerTrades(app.brokers, app.jobRunner, app.logger))
+			r.Get("/integrations/connections", connectionsHandler.ListConnections)
+			r.Post("/integrations/connections", connectionsHandler.CreateConnection)
+			r.Get("/integrations/connections/{id}", connectionsHandler.GetConnection)
+			r.Put("/integrations/connections/{id}", connectionsHandler.UpdateConnection)
+			r.Delete("/integrations/connections/{id}", connectionsHandler.DeleteConnection)
+			r.Post("/integrations/connections/{id}/test", connectionsHandler.TestConnection)
+			r.Post("/integrations/connections/{id}/sync", connectionsHandler.SyncConnection)
+
+			// Background jobs
+			r.Get("/jobs", handlers.ListJobs(app.db, app.logger))
+			r.Get("/jobs/{id}", handlers.GetJob(app.db, app.logger))
 		})
 	})
 
@@ -254,6 +375,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Stop job workers; running jobs are canceled and marked as failed
+	stopJobs()
+	select {
+	case <-jobsDone:
+	case <-ctx.Done():
+		logger.Warn("Job runner did not stop in time")
+	}
+
 	logger.Info("Server exited properly")
 }
 
@@ -0,0 +1,254 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/models"
+)
+
+const accountColumns = `
+		id, user_id, name, broker, account_number, currency, account_type,
+		starting_balance, is_active, import_batch_id, created_at, updated_at`
+
+// ListAccounts retrieves all accounts for a user
+func (db *DB) ListAccounts(ctx context.Context, userID uuid.UUID) ([]models.Account, error) {
+	query := `SELECT` + accountColumns + `
+		FROM accounts
+		WHERE user_id = $1
+		ORDER BY is_active DESC, name ASC`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	defer rows.Close()
+
+	// Initialize to empty slice to avoid null JSON serialization
+	accounts := make([]models.Account, 0)
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// GetAccount retrieves a single account by ID
+func (db *DB) GetAccount(ctx context.Context, id, userID uuid.UUID) (*models.Account, error) {
+	query := `SELECT` + accountColumns + `
+		FROM accounts
+		WHERE id = $1 AND user_id = $2`
+
+	account, err := scanAccount(db.QueryRowContext(ctx, query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// CreateAccount inserts a new account
+func (db *DB) CreateAccount(ctx context.Context, account *models.Account) error {
+	return insertAccount(ctx, db, account)
+}
+
+// UpdateAccount updates an existing account
+func (db *DB) UpdateAccount(ctx context.Context, id, userID uuid.UUID, account *models.Account) error {
+	query := `
+		UPDATE accounts
+		SET name = $3, broker = $4, account_number = $5, currency = $6, account_type = $7,
+		    starting_balance = $8, is_active = $9
+		WHERE id = $1 AND user_id = $2
+		RETURNING created_at, updated_at`
+
+	err := db.QueryRowContext(
+		ctx,
+		query,
+		id, userID, account.Name, account.Broker, account.AccountNumber, account.Currency,
+		account.AccountType, account.StartingBalance, account.IsActive,
+	).Scan(&account.CreatedAt, &account.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("account not found or unauthorized")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update account: %w", err)
+	}
+
+	account.ID = id
+	account.UserID = userID
+
+	return nil
+}
+
+// DeleteAccount deletes an account. Its trades are kept and become unassigned.
+func (db *DB) DeleteAccount(ctx context.Context, id, userID uuid.UUID) error {
+	result, err := db.ExecContext(ctx, `DELETE FROM accounts WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("account not found or unauthorized")
+	}
+
+	return nil
+}
+
+// queryRower is implemented by both *DB and *sql.Tx
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func insertAccount(ctx context.Context, q queryRower, account *models.Account) error {
+	query := `
+		INSERT INTO accounts (
+			user_id, name, broker, account_number, currency, account_type, starting_balance, is_active, import_batch_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at`
+
+	err := q.QueryRowContext(
+		ctx,
+		query,
+		account.UserID, account.Name, account.Broker, account.AccountNumber, account.Currency,
+		account.AccountType, account.StartingBalance, account.IsActive, account.ImportBatchID,
+	).Scan(&account.ID, &account.CreatedAt, &account.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create account: %w", err)
+	}
+
+	return nil
+}
+
+// accountResolver finds the accounts that imported trades and cash entries
+// belong to. With an account chosen for the import, everything goes to it.
+// Otherwise rows go to the account whose number matches the one the broker
+// reported them under, and accounts seen for the first time are created,
+// named after their number and tagged with the import batch.
+type accountResolver struct {
+	tx       *sql.Tx
+	opts     ImportOptions
+	batchID  *uuid.UUID
+	byNumber map[string]uuid.UUID
+}
+
+func newAccountResolver(tx *sql.Tx, opts ImportOptions, batchID *uuid.UUID) *accountResolver {
+	return &accountResolver{tx: tx, opts: opts, batchID: batchID, byNumber: make(map[string]uuid.UUID)}
+}
+
+// resolve returns the account for a broker account number, or nil when there
+// is neither a chosen account nor a number to match
+func (a *accountResolver) resolve(ctx context.Context, userID uuid.UUID, number, currency string) (*uuid.UUID, error) {
+	if a.opts.AccountID != nil {
+		id := *a.opts.AccountID
+		return &id, nil
+	}
+	if number == "" {
+		return nil, nil
+	}
+
+	id, ok := a.byNumber[number]
+	if !ok {
+		var err error
+		id, err = findOrCreateAccount(ctx, a.tx, userID, number, currency, a.opts.Source, a.batchID)
+		if err != nil {
+			return nil, err
+		}
+		a.byNumber[number] = id
+	}
+	return &id, nil
+}
+
+// assignAccounts books imported trades that have no account yet
+func assignAccounts(ctx context.Context, accounts *accountResolver, trades []models.Trade) error {
+	for i := range trades {
+		trade := &trades[i]
+		if trade.AccountID != nil {
+			continue
+		}
+
+		id, err := accounts.resolve(ctx, trade.UserID, tradeAccountNumber(*trade), trade.CurrencyCode())
+		if err != nil {
+			return err
+		}
+		trade.AccountID = id
+	}
+
+	return nil
+}
+
+func findOrCreateAccount(ctx context.Context, tx *sql.Tx, userID uuid.UUID, number, currency string, source models.ImportSource, batchID *uuid.UUID) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, `
+		SELECT id FROM accounts WHERE user_id = $1 AND account_number = $2`,
+		userID, number,
+	).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		return uuid.Nil, fmt.Errorf("failed to look up account: %w", err)
+	}
+
+	account := models.Account{
+		UserID:        userID,
+		Name:          number,
+		Broker:        string(source),
+		AccountNumber: number,
+		Currency:      currency,
+		AccountType:   models.AccountCash,
+		IsActive:      true,
+		ImportBatchID: batchID,
+	}
+	if err := insertAccount(ctx, tx, &account); err != nil {
+		return uuid.Nil, err
+	}
+
+	return account.ID, nil
+}
+
+// tradeAccountNumber returns the broker account a trade's executions were reported under
+func tradeAccountNumber(trade models.Trade) string {
+	for _, e := range trade.Executions {
+		if e.Account != "" {
+			return e.Account
+		}
+	}
+	return ""
+}
+
+func scanAccount(row rowScanner) (*models.Account, error) {
+	var account models.Account
+
+	err := row.Scan(
+		&account.ID, &account.UserID, &account.Name, &account.Broker, &account.AccountNumber,
+		&account.Currency, &account.AccountType, &account.StartingBalance, &account.IsActive,
+		&account.ImportBatchID, &account.CreatedAt, &account.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan account: %w", err)
+	}
+
+	return &account, nil
+}
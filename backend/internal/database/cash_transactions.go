@@ -0,0 +1,384 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/models"
+)
+
+const cashTransactionColumns = `
+		id, user_id, account_id, type, amount, description, reference, occurred_at,
+		fingerprint, import_batch_id, created_at, updated_at`
+
+// CashTransactionFilters represents filters for listing cash transactions
+type CashTransactionFilters struct {
+	AccountID string
+	Type      string
+	StartDate string
+	EndDate   string
+}
+
+// ListCashTransactions retrieves a user's cash ledger, most recent first
+func (db *DB) ListCashTransactions(ctx context.Context, userID uuid.UUID, filters CashTransactionFilters) ([]models.CashTransaction, error) {
+	query := `SELECT` + cashTransactionColumns + `
+		FROM cash_transactions
+		WHERE user_id = $1`
+
+	args := []interface{}{userID}
+	argCount := 1
+
+	if filters.AccountID != "" {
+		argCount++
+		query += fmt.Sprintf(" AND account_id = $%d", argCount)
+		args = append(args, filters.AccountID)
+	}
+
+	if filters.Type != "" {
+		argCount++
+		query += fmt.Sprintf(" AND type = $%d", argCount)
+		args = append(args, filters.Type)
+	}
+
+	if filters.StartDate != "" {
+		argCount++
+		query += fmt.Sprintf(" AND occurred_at >= $%d", argCount)
+		args = append(args, filters.StartDate)
+	}
+
+	if filters.EndDate != "" {
+		argCount++
+		query += fmt.Sprintf(" AND occurred_at <= $%d", argCount)
+		args = append(args, filters.EndDate)
+	}
+
+	query += " ORDER BY occurred_at DESC, created_at DESC"
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cash transactions: %w", err)
+	}
+	defer rows.Close()
+
+	// Initialize to empty slice to avoid null JSON serialization
+	transactions := make([]models.CashTransaction, 0)
+	for rows.Next() {
+		transaction, err := scanCashTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, *transaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cash transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetCashTransaction retrieves a single cash transaction by ID
+func (db *DB) GetCashTransaction(ctx context.Context, id, userID uuid.UUID) (*models.CashTransaction, error) {
+	query := `SELECT` + cashTransactionColumns + `
+		FROM cash_transactions
+		WHERE id = $1 AND user_id = $2`
+
+	transaction, err := scanCashTransaction(db.QueryRowContext(ctx, query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// CreateCashTransaction inserts a manually entered cash transaction
+func (db *DB) CreateCashTransaction(ctx context.Context, transaction *models.CashTransaction) error {
+	query := `
+		INSERT INTO cash_transactions (
+			user_id, account_id, type, amount, description, reference, occurred_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at`
+
+	err := db.QueryRowContext(
+		ctx,
+		query,
+		transaction.UserID, transaction.AccountID, transaction.Type, transaction.Amount,
+		transaction.Description, transaction.Reference, transaction.OccurredAt,
+	).Scan(&transaction.ID, &transaction.CreatedAt, &transaction.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create cash transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateCashTransaction updates an existing cash transaction
+func (db *DB) UpdateCashTransaction(ctx context.Context, id, userID uuid.UUID, transaction *models.CashTransaction) error {
+	query := `
+		UPDATE cash_transactions
+		SET account_id = $3, type = $4, amount = $5, description = $6, reference = $7, occurred_at = $8
+		WHERE id = $1 AND user_id = $2
+		RETURNING fingerprint, import_batch_id, created_at, updated_at`
+
+	err := db.QueryRowContext(
+		ctx,
+		query,
+		id, userID, transaction.AccountID, transaction.Type, transaction.Amount,
+		transaction.Description, transaction.Reference, transaction.OccurredAt,
+	).Scan(&transaction.Fingerprint, &transaction.ImportBatchID, &transaction.CreatedAt, &transaction.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("cash transaction not found or unauthorized")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update cash transaction: %w", err)
+	}
+
+	transaction.ID = id
+	transaction.UserID = userID
+
+	return nil
+}
+
+// DeleteCashTransaction deletes a cash transaction
+func (db *DB) DeleteCashTransaction(ctx context.Context, id, userID uuid.UUID) error {
+	result, err := db.ExecContext(ctx, `DELETE FROM cash_transactions WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete cash transaction: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("cash transaction not found or unauthorized")
+	}
+
+	return nil
+}
+
+// insertImportedCash records the cash entries of an import. Entries are
+// fingerprinted like trades, so statements that overlap are only counted once.
+func insertImportedCash(ctx context.Context, tx *sql.Tx, accounts *accountResolver, opts ImportOptions, batch *models.ImportBatch, result *ImportResult) error {
+	currency := opts.Currency
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
+	for _, transaction := range opts.Cash {
+		accountID, err := accounts.resolve(ctx, transaction.UserID, transaction.Account, currency)
+		if err != nil {
+			return err
+		}
+		if accountID == nil {
+			result.CashUnassigned++
+			continue
+		}
+
+		if transaction.Fingerprint == "" {
+			transaction.Fingerprint = transaction.ComputeFingerprint()
+		}
+		var batchID *uuid.UUID
+		if batch != nil {
+			batchID = &batch.ID
+		}
+
+		inserted, err := tx.ExecContext(ctx, `
+			INSERT INTO cash_transactions (
+				user_id, account_id, type, amount, description, reference, occurred_at,
+				fingerprint, import_batch_id
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (account_id, fingerprint) WHERE fingerprint <> '' DO NOTHING`,
+			transaction.UserID, *accountID, transaction.Type, transaction.Amount, transaction.Description,
+			transaction.Reference, transaction.OccurredAt, transaction.Fingerprint, batchID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert cash transaction: %w", err)
+		}
+
+		if n, _ := inserted.RowsAffected(); n > 0 {
+			result.CashInserted++
+		} else {
+			result.CashDuplicates++
+		}
+	}
+
+	return nil
+}
+
+// MaxBalanceDays is the longest daily balance series GetAccountBalances builds
+const MaxBalanceDays = 3660
+
+// ErrBalanceRangeTooLong is returned for balance series longer than MaxBalanceDays
+var ErrBalanceRangeTooLong = errors.New("balance range is too long")
+
+// GetAccountBalances builds an account's daily balance series from its
+// starting balance, cash ledger and the realized P&L of trades closed in it.
+// Days are calendar days in loc and run from startDate (default: the first day
+// with activity) to endDate (default: the last), both YYYY-MM-DD. Ranges of more
+// than MaxBalanceDays fail with ErrBalanceRangeTooLong.
+func (db *DB) GetAccountBalances(ctx context.Context, account *models.Account, loc *time.Location, startDate, endDate string) (*models.AccountBalanceSeries, error) {
+	days := make(map[string]*models.AccountBalance)
+	day := func(date time.Time) *models.AccountBalance {
+		key := date.Format("2006-01-02")
+		if days[key] == nil {
+			days[key] = &models.AccountBalance{Date: key}
+		}
+		return days[key]
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT (occurred_at AT TIME ZONE $3)::date, type, SUM(amount)
+		FROM cash_transactions
+		WHERE account_id = $1 AND user_id = $2
+		GROUP BY 1, 2`,
+		account.ID, account.UserID, loc.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum cash transactions: %w", err)
+	}
+	for rows.Next() {
+		var date time.Time
+		var kind models.CashTransactionType
+		var amount float64
+		if err := rows.Scan(&date, &kind, &amount); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan cash totals: %w", err)
+		}
+		if kind.IsFunding() {
+			day(date).Funding += amount
+		} else {
+			day(date).CashIncome += amount
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cash totals: %w", err)
+	}
+
+	rows, err = db.QueryContext(ctx, `
+		SELECT (closed_at AT TIME ZONE $3)::date, SUM(pnl)
+		FROM trades
+		WHERE account_id = $1 AND user_id = $2 AND closed_at IS NOT NULL AND pnl IS NOT NULL
+		GROUP BY 1`,
+		account.ID, account.UserID, loc.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum realized P&L: %w", err)
+	}
+	for rows.Next() {
+		var date time.Time
+		var pnl float64
+		if err := rows.Scan(&date, &pnl); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan realized P&L: %w", err)
+		}
+		day(date).RealizedPnL += pnl
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating realized P&L: %w", err)
+	}
+
+	series := &models.AccountBalanceSeries{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		OpeningBalance: account.StartingBalance,
+		EndingBalance:  account.StartingBalance,
+		Days:           make([]models.AccountBalance, 0),
+	}
+
+	active := make([]string, 0, len(days))
+	for key := range days {
+		active = append(active, key)
+	}
+	sort.Strings(active)
+
+	from, to := startDate, endDate
+	if from == "" && len(active) > 0 {
+		from = active[0]
+	}
+	if to == "" && len(active) > 0 {
+		to = active[len(active)-1]
+	}
+	if from == "" || to == "" || from > to {
+		return series, nil
+	}
+
+	// Activity before the range only moves the balance the range opens with
+	balance := account.StartingBalance
+	for _, key := range active {
+		if key >= from {
+			break
+		}
+		balance += days[key].Funding + days[key].CashIncome + days[key].RealizedPnL
+	}
+	series.OpeningBalance = balance
+
+	first, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %w", err)
+	}
+	last, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %w", err)
+	}
+	if last.Sub(first) >= MaxBalanceDays*24*time.Hour {
+		return nil, ErrBalanceRangeTooLong
+	}
+
+	growth := 1.0
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		point := models.AccountBalance{Date: date.Format("2006-01-02")}
+		if activity, ok := days[point.Date]; ok {
+			point = *activity
+		}
+
+		point.OpeningBalance = balance
+		gain := point.CashIncome + point.RealizedPnL
+		balance += point.Funding + gain
+		point.Balance = balance
+		if point.OpeningBalance > 0 {
+			point.ReturnPct = gain / point.OpeningBalance * 100
+			growth *= 1 + gain/point.OpeningBalance
+		}
+
+		series.TotalFunding += point.Funding
+		series.TotalCashIncome += point.CashIncome
+		series.TotalRealized += point.RealizedPnL
+		series.Days = append(series.Days, point)
+	}
+
+	series.EndingBalance = balance
+	series.ReturnPct = (growth - 1) * 100
+
+	return series, nil
+}
+
+func scanCashTransaction(row rowScanner) (*models.CashTransaction, error) {
+	var transaction models.CashTransaction
+
+	err := row.Scan(
+		&transaction.ID, &transaction.UserID, &transaction.AccountID, &transaction.Type,
+		&transaction.Amount, &transaction.Description, &transaction.Reference, &transaction.OccurredAt,
+		&transaction.Fingerprint, &transaction.ImportBatchID, &transaction.CreatedAt, &transaction.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan cash transaction: %w", err)
+	}
+
+	return &transaction, nil
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/models"
+)
+
+// openTestDB connects to the Postgres database named by TEST_DATABASE_URL and
+// migrates it. Tests that need a database are skipped when it is not set.
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	conn, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := &DB{conn}
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := filepath.Abs(filepath.Join("..", "..", "migrations"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RunMigrations(migrations); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// createTestUser adds a user that is deleted, with everything it owns, when the test ends
+func createTestUser(t *testing.T, db *DB) uuid.UUID {
+	t.Helper()
+
+	user := &models.User{
+		ID:         uuid.New(),
+		Email:      uuid.NewString() + "@example.com",
+		PlanType:   "starter",
+		PlanStatus: "beta_free",
+		CreatedAt:  time.Now(),
+	}
+	if err := db.CreateUser(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if _, err := db.Exec(`DELETE FROM users WHERE id = $1`, user.ID); err != nil {
+			t.Errorf("failed to delete test user: %v", err)
+		}
+	})
+	return user.ID
+}
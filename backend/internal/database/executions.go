@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/models"
+)
+
+const executionColumns = `
+	id, trade_id, user_id, account, symbol, side, quantity, price, executed_at,
+	route, liquidity, order_id, fill_id, commission, ecn_fee, sec_fee, taf_fee,
+	nscc_fee, clearing_fee, misc_fee, multiplier, realized_pnl, foreign_quote, fingerprint, import_batch_id, created_at`
+
+// insertExecutions stores the executions for a trade inside an existing transaction
+func insertExecutions(ctx context.Context, tx *sql.Tx, tradeID, userID uuid.UUID, executions []models.Execution) error {
+	stmt := `
+		INSERT INTO executions (
+			trade_id, user_id, account, symbol, side, quantity, price, executed_at,
+			route, liquidity, order_id, fill_id, commission, ecn_fee, sec_fee, taf_fee,
+			nscc_fee, clearing_fee, misc_fee, multiplier, realized_pnl, foreign_quote, fingerprint, import_batch_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+		RETURNING id, created_at`
+
+	for i := range executions {
+		e := &executions[i]
+		e.TradeID = tradeID
+		e.UserID = userID
+
+		err := tx.QueryRowContext(
+			ctx,
+			stmt,
+			e.TradeID, e.UserID, e.Account, e.Symbol, e.Side, e.Quantity, e.Price, e.ExecutedAt,
+			e.Route, e.Liquidity, e.OrderID, e.FillID, e.Commission, e.ECNFee, e.SECFee, e.TAFFee,
+			e.NSCCFee, e.ClearingFee, e.MiscFee, e.ContractMultiplier(), e.RealizedPnL, e.ForeignQuote, e.Fingerprint, e.ImportBatchID,
+		).Scan(&e.ID, &e.CreatedAt)
+
+		if err != nil {
+			return fmt.Errorf("failed to insert execution: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ListExecutionsByTradeID retrieves all executions for a trade in time order
+func (db *DB) ListExecutionsByTradeID(ctx context.Context, tradeID, userID uuid.UUID) ([]models.Execution, error) {
+	return listExecutions(ctx, db, tradeID, userID)
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func listExecutions(ctx context.Context, q queryer, tradeID, userID uuid.UUID) ([]models.Execution, error) {
+	query := `SELECT ` + executionColumns + `
+		FROM executions
+		WHERE trade_id = $1 AND user_id = $2
+		ORDER BY executed_at ASC, created_at ASC`
+
+	rows, err := q.QueryContext(ctx, query, tradeID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list executions: %w", err)
+	}
+	defer rows.Close()
+
+	// Initialize to empty slice to avoid null JSON serialization
+	executions := make([]models.Execution, 0)
+	for rows.Next() {
+		var e models.Execution
+		err := rows.Scan(
+			&e.ID, &e.TradeID, &e.UserID, &e.Account, &e.Symbol, &e.Side, &e.Quantity, &e.Price, &e.ExecutedAt,
+			&e.Route, &e.Liquidity, &e.OrderID, &e.FillID, &e.Commission, &e.ECNFee, &e.SECFee, &e.TAFFee,
+			&e.NSCCFee, &e.ClearingFee, &e.MiscFee, &e.Multiplier, &e.RealizedPnL, &e.ForeignQuote, &e.Fingerprint, &e.ImportBatchID, &e.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan execution: %w", err)
+		}
+		executions = append(executions, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating executions: %w", err)
+	}
+
+	return executions, nil
+}
+
+// Errors returned by RebuildTradeFromExecutions
+var (
+	ErrTradeNotFound = errors.New("trade not found or unauthorized")
+	ErrNoExecutions  = errors.New("trade has no executions")
+)
+
+// RebuildTradeFromExecutions recalculates a trade's quantity, prices, fees and
+// timestamps from its stored executions and saves the result. It fails with
+// ErrTradeNotFound or ErrNoExecutions when there is nothing to rebuild.
+func (db *DB) RebuildTradeFromExecutions(ctx context.Context, tradeID, userID uuid.UUID) (*models.Trade, error) {
+	trade, err := db.GetTrade(ctx, tradeID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if trade == nil {
+		return nil, ErrTradeNotFound
+	}
+	if len(trade.Executions) == 0 {
+		return nil, ErrNoExecutions
+	}
+
+	trade.RebuildFromExecutions()
+
+	if err := db.UpdateTrade(ctx, tradeID, userID, trade); err != nil {
+		return nil, err
+	}
+
+	return trade, nil
+}
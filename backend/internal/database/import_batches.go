@@ -0,0 +1,253 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/models"
+)
+
+const importBatchColumns = `
+	id, user_id, source, file_name, status, row_count, inserted_count, updated_count,
+	duplicate_count, conflict_count, rolled_back_at, created_at, updated_at`
+
+// ImportRollback reports what rolling back an import batch removed
+type ImportRollback struct {
+	Batch             *models.ImportBatch `json:"batch"`
+	DeletedTrades     int                 `json:"deleted_trades"`
+	DeletedExecutions int                 `json:"deleted_executions"`
+	RebuiltTrades     int                 `json:"rebuilt_trades"`
+	DeletedCash       int                 `json:"deleted_cash_transactions"`
+	DeletedAccounts   int                 `json:"deleted_accounts"`
+}
+
+// createImportBatch records the start of an import inside an existing transaction
+func createImportBatch(ctx context.Context, tx *sql.Tx, trades []models.Trade, opts ImportOptions) (*models.ImportBatch, error) {
+	var userID uuid.UUID
+	switch {
+	case len(trades) > 0:
+		userID = trades[0].UserID
+	case len(opts.Cash) > 0:
+		userID = opts.Cash[0].UserID
+	default:
+		return nil, fmt.Errorf("no trades to import")
+	}
+
+	source := opts.Source
+	if source == "" {
+		source = models.ImportSourceCSV
+	}
+
+	query := `
+		INSERT INTO import_batches (user_id, source, file_name, row_count)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + importBatchColumns
+
+	return scanImportBatch(tx.QueryRowContext(ctx, query, userID, source, opts.FileName, len(trades)))
+}
+
+// finishImportBatch stores the outcome counts of an import
+func finishImportBatch(ctx context.Context, tx *sql.Tx, batchID uuid.UUID, result *ImportResult) error {
+	query := `
+		UPDATE import_batches
+		SET inserted_count = $2, updated_count = $3, duplicate_count = $4, conflict_count = $5
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, batchID, result.Inserted, result.Updated, result.Duplicates, result.Conflicts)
+	if err != nil {
+		return fmt.Errorf("failed to update import batch: %w", err)
+	}
+
+	return nil
+}
+
+// ListImportBatches retrieves a user's import batches, most recent first
+func (db *DB) ListImportBatches(ctx context.Context, userID uuid.UUID) ([]models.ImportBatch, error) {
+	query := `SELECT ` + importBatchColumns + `
+		FROM import_batches
+		WHERE user_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import batches: %w", err)
+	}
+	defer rows.Close()
+
+	// Initialize to empty slice to avoid null JSON serialization
+	batches := make([]models.ImportBatch, 0)
+	for rows.Next() {
+		batch, err := scanImportBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, *batch)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating import batches: %w", err)
+	}
+
+	return batches, nil
+}
+
+// GetImportBatch retrieves a single import batch by ID
+func (db *DB) GetImportBatch(ctx context.Context, id, userID uuid.UUID) (*models.ImportBatch, error) {
+	query := `SELECT ` + importBatchColumns + `
+		FROM import_batches
+		WHERE id = $1 AND user_id = $2`
+
+	batch, err := scanImportBatch(db.QueryRowContext(ctx, query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// RollbackImportBatch undoes an import. Trades created by the batch are deleted
+// together with their executions, tags and journal entries. Executions the batch
+// added to trades from earlier imports are removed and those trades rebuilt.
+// Trades imported without executions that the batch overwrote keep the new values.
+// Accounts the batch created are deleted if they hold nothing else and have not
+// been edited since.
+func (db *DB) RollbackImportBatch(ctx context.Context, id, userID uuid.UUID) (*ImportRollback, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + importBatchColumns + `
+		FROM import_batches
+		WHERE id = $1 AND user_id = $2
+		FOR UPDATE`
+
+	batch, err := scanImportBatch(tx.QueryRowContext(ctx, query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("import batch not found or unauthorized")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status == models.ImportBatchRolledBack {
+		return nil, fmt.Errorf("import batch was already rolled back")
+	}
+
+	rollback := &ImportRollback{}
+
+	// Trades from earlier imports that this batch extended
+	rows, err := tx.QueryContext(ctx, `
+		SELECT DISTINCT e.trade_id
+		FROM executions e
+		JOIN trades t ON t.id = e.trade_id
+		WHERE e.import_batch_id = $1 AND e.user_id = $2
+		  AND t.import_batch_id IS DISTINCT FROM $1`,
+		id, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find extended trades: %w", err)
+	}
+
+	extended := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var tradeID uuid.UUID
+		if err := rows.Scan(&tradeID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan extended trade: %w", err)
+		}
+		extended = append(extended, tradeID)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating extended trades: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM executions e
+		USING trades t
+		WHERE t.id = e.trade_id AND e.import_batch_id = $1 AND e.user_id = $2
+		  AND t.import_batch_id IS DISTINCT FROM $1`,
+		id, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete batch executions: %w", err)
+	}
+	deleted, _ := result.RowsAffected()
+	rollback.DeletedExecutions = int(deleted)
+
+	// Executions, order events, tags and journal entries cascade with the trade
+	result, err = tx.ExecContext(ctx, `DELETE FROM trades WHERE import_batch_id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete batch trades: %w", err)
+	}
+	deleted, _ = result.RowsAffected()
+	rollback.DeletedTrades = int(deleted)
+
+	result, err = tx.ExecContext(ctx, `DELETE FROM cash_transactions WHERE import_batch_id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete batch cash transactions: %w", err)
+	}
+	deleted, _ = result.RowsAffected()
+	rollback.DeletedCash = int(deleted)
+
+	for _, tradeID := range extended {
+		if err := rebuildTrade(ctx, tx, tradeID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err = tx.ExecContext(ctx, `
+		DELETE FROM accounts a
+		WHERE a.import_batch_id = $1 AND a.user_id = $2 AND a.updated_at = a.created_at
+		  AND NOT EXISTS (SELECT 1 FROM trades t WHERE t.account_id = a.id)
+		  AND NOT EXISTS (SELECT 1 FROM cash_transactions c WHERE c.account_id = a.id)`,
+		id, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete batch accounts: %w", err)
+	}
+	deleted, _ = result.RowsAffected()
+	rollback.DeletedAccounts = int(deleted)
+	rollback.RebuiltTrades = len(extended)
+
+	query = `
+		UPDATE import_batches
+		SET status = $3, rolled_back_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + importBatchColumns
+
+	rollback.Batch, err = scanImportBatch(tx.QueryRowContext(ctx, query, id, userID, models.ImportBatchRolledBack))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return rollback, nil
+}
+
+func scanImportBatch(row rowScanner) (*models.ImportBatch, error) {
+	var batch models.ImportBatch
+
+	err := row.Scan(
+		&batch.ID, &batch.UserID, &batch.Source, &batch.FileName, &batch.Status,
+		&batch.RowCount, &batch.InsertedCount, &batch.UpdatedCount, &batch.DuplicateCount,
+		&batch.ConflictCount, &batch.RolledBackAt, &batch.CreatedAt, &batch.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan import batch: %w", err)
+	}
+
+	return &batch, nil
+}
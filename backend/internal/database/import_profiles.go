@@ -0,0 +1,188 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/models"
+)
+
+// ListImportProfiles retrieves all import profiles for a user
+func (db *DB) ListImportProfiles(ctx context.Context, userID uuid.UUID) ([]models.ImportProfile, error) {
+	query := `
+		SELECT id, user_id, name, column_mapping, date_format, time_format, side_mapping,
+		       timezone, delimiter, fees_as_debits, created_at, updated_at
+		FROM import_profiles
+		WHERE user_id = $1
+		ORDER BY name ASC`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import profiles: %w", err)
+	}
+	defer rows.Close()
+
+	// Initialize to empty slice to avoid null JSON serialization
+	profiles := make([]models.ImportProfile, 0)
+	for rows.Next() {
+		profile, err := scanImportProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, *profile)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating import profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// GetImportProfile retrieves a single import profile by ID
+func (db *DB) GetImportProfile(ctx context.Context, id, userID uuid.UUID) (*models.ImportProfile, error) {
+	query := `
+		SELECT id, user_id, name, column_mapping, date_format, time_format, side_mapping,
+		       timezone, delimiter, fees_as_debits, created_at, updated_at
+		FROM import_profiles
+		WHERE id = $1 AND user_id = $2`
+
+	profile, err := scanImportProfile(db.QueryRowContext(ctx, query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// CreateImportProfile inserts a new import profile
+func (db *DB) CreateImportProfile(ctx context.Context, profile *models.ImportProfile) error {
+	columnMapping, sideMapping, err := marshalProfileMappings(profile)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO import_profiles (
+			user_id, name, column_mapping, date_format, time_format, side_mapping, timezone, delimiter, fees_as_debits
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at`
+
+	err = db.QueryRowContext(
+		ctx,
+		query,
+		profile.UserID, profile.Name, columnMapping, profile.DateFormat, profile.TimeFormat,
+		sideMapping, profile.Timezone, profile.Delimiter, profile.FeesAsDebits,
+	).Scan(&profile.ID, &profile.CreatedAt, &profile.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create import profile: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateImportProfile updates an existing import profile
+func (db *DB) UpdateImportProfile(ctx context.Context, id, userID uuid.UUID, profile *models.ImportProfile) error {
+	columnMapping, sideMapping, err := marshalProfileMappings(profile)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE import_profiles
+		SET name = $3, column_mapping = $4, date_format = $5, time_format = $6,
+		    side_mapping = $7, timezone = $8, delimiter = $9, fees_as_debits = $10
+		WHERE id = $1 AND user_id = $2
+		RETURNING created_at, updated_at`
+
+	err = db.QueryRowContext(
+		ctx,
+		query,
+		id, userID, profile.Name, columnMapping, profile.DateFormat, profile.TimeFormat,
+		sideMapping, profile.Timezone, profile.Delimiter, profile.FeesAsDebits,
+	).Scan(&profile.CreatedAt, &profile.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("import profile not found or unauthorized")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update import profile: %w", err)
+	}
+
+	profile.ID = id
+	profile.UserID = userID
+
+	return nil
+}
+
+// DeleteImportProfile deletes an import profile
+func (db *DB) DeleteImportProfile(ctx context.Context, id, userID uuid.UUID) error {
+	result, err := db.ExecContext(ctx, `DELETE FROM import_profiles WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete import profile: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("import profile not found or unauthorized")
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanImportProfile(row rowScanner) (*models.ImportProfile, error) {
+	var profile models.ImportProfile
+	var columnMapping, sideMapping []byte
+
+	err := row.Scan(
+		&profile.ID, &profile.UserID, &profile.Name, &columnMapping, &profile.DateFormat,
+		&profile.TimeFormat, &sideMapping, &profile.Timezone, &profile.Delimiter, &profile.FeesAsDebits,
+		&profile.CreatedAt, &profile.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan import profile: %w", err)
+	}
+
+	if err := json.Unmarshal(columnMapping, &profile.ColumnMapping); err != nil {
+		return nil, fmt.Errorf("failed to decode column mapping: %w", err)
+	}
+	if err := json.Unmarshal(sideMapping, &profile.SideMapping); err != nil {
+		return nil, fmt.Errorf("failed to decode side mapping: %w", err)
+	}
+
+	return &profile, nil
+}
+
+func marshalProfileMappings(profile *models.ImportProfile) ([]byte, []byte, error) {
+	columnMapping, err := json.Marshal(profile.ColumnMapping)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode column mapping: %w", err)
+	}
+
+	sideMapping := []byte("{}")
+	if profile.SideMapping != nil {
+		sideMapping, err = json.Marshal(profile.SideMapping)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode side mapping: %w", err)
+		}
+	}
+
+	return columnMapping, sideMapping, nil
+}
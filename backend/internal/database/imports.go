@@ -0,0 +1,511 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/tradepulse/api/internal/models"
+)
+
+// DuplicateMode controls what an import does with trades that were imported before
+type DuplicateMode string
+
+const (
+	// DuplicateSkip leaves previously imported trades untouched and flags any
+	// that came back with different executions as conflicts
+	DuplicateSkip DuplicateMode = "skip"
+	// DuplicateUpdate adds new executions to previously imported trades and
+	// recalculates them
+	DuplicateUpdate DuplicateMode = "update"
+)
+
+// ImportOptions controls how BulkCreateTrades records an import and treats
+// re-imported data
+type ImportOptions struct {
+	OnDuplicate DuplicateMode
+	Source      models.ImportSource
+	FileName    string
+	// AccountID books every trade and cash entry to the account; when nil,
+	// they are booked by the account number the broker reported them under
+	AccountID *uuid.UUID
+	// Cash holds deposits, fees and other ledger entries from the same statement
+	Cash []models.CashTransaction
+	// Currency is given to accounts created for cash entries (default USD)
+	Currency string
+	// DryRun classifies the trades without writing anything
+	DryRun bool
+}
+
+// ImportConflict describes an incoming trade that could not be imported safely
+type ImportConflict struct {
+	Index           int        `json:"index"`
+	Symbol          string     `json:"symbol"`
+	OpenedAt        time.Time  `json:"opened_at"`
+	Reason          string     `json:"reason"`
+	ExistingTradeID *uuid.UUID `json:"existing_trade_id,omitempty"`
+}
+
+// ImportResult reports what happened to each trade of an import
+type ImportResult struct {
+	BatchID    *uuid.UUID       `json:"batch_id,omitempty"`
+	Inserted   int              `json:"inserted"`
+	Updated    int              `json:"updated"`
+	Duplicates int              `json:"duplicates"`
+	Conflicts  int              `json:"conflicts"`
+	TradeIDs   []uuid.UUID      `json:"trade_ids"`
+	Conflicted []ImportConflict `json:"conflicted"`
+	// Cash entries recorded, already in the ledger, and left out for want of an account
+	CashInserted   int `json:"cash_inserted"`
+	CashDuplicates int `json:"cash_duplicates"`
+	CashUnassigned int `json:"cash_unassigned"`
+}
+
+// ParseDuplicateMode validates a duplicate mode from a request, defaulting to skip
+func ParseDuplicateMode(value string) (DuplicateMode, error) {
+	switch DuplicateMode(value) {
+	case "", DuplicateSkip:
+		return DuplicateSkip, nil
+	case DuplicateUpdate:
+		return DuplicateUpdate, nil
+	}
+	return "", fmt.Errorf("on_duplicate must be %q or %q", DuplicateSkip, DuplicateUpdate)
+}
+
+// BulkCreateTrades imports multiple trades (for CSV and broker imports) as one
+// import batch. Every trade and execution is fingerprinted so that importing the
+// same data again does not create duplicates:
+//   - a trade whose fingerprint and executions are all known is a duplicate and skipped
+//   - a known trade that arrives with new executions is updated in DuplicateUpdate
+//     mode and flagged as a conflict otherwise
+//   - a trade continuing a position an earlier import left open is updated in
+//     either mode, since its new executions are that position's later fills
+//   - a new trade containing executions already imported into another trade is a conflict
+func (db *DB) BulkCreateTrades(ctx context.Context, trades []models.Trade, opts ImportOptions) (*ImportResult, error) {
+	// A dry run must not leave IDs from its discarded inserts on the caller's trades
+	if opts.DryRun {
+		trades = cloneTrades(trades)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &ImportResult{
+		TradeIDs:   make([]uuid.UUID, 0, len(trades)),
+		Conflicted: make([]ImportConflict, 0),
+	}
+
+	var batch *models.ImportBatch
+	if !opts.DryRun {
+		batch, err = createImportBatch(ctx, tx, trades, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.BatchID = &batch.ID
+	}
+
+	accounts := newAccountResolver(tx, opts, result.BatchID)
+	if err := assignAccounts(ctx, accounts, trades); err != nil {
+		return nil, err
+	}
+
+	for i := range trades {
+		trade := &trades[i]
+		assignFingerprints(trade)
+		if batch != nil {
+			assignImportBatch(trade, batch.ID)
+		}
+
+		conflict := func(reason string, existingID *uuid.UUID) {
+			result.Conflicts++
+			result.Conflicted = append(result.Conflicted, ImportConflict{
+				Index:           i,
+				Symbol:          trade.Symbol,
+				OpenedAt:        trade.OpenedAt,
+				Reason:          reason,
+				ExistingTradeID: existingID,
+			})
+		}
+
+		existing, err := findImportedTrade(ctx, tx, trade.UserID, trade.Fingerprint)
+		if err != nil {
+			return nil, err
+		}
+
+		owners, err := executionOwners(ctx, tx, trade.UserID, trade.Executions)
+		if err != nil {
+			return nil, err
+		}
+
+		var otherOwner *uuid.UUID
+		for _, owner := range owners {
+			if existing == nil || owner != existing.ID {
+				owner := owner
+				otherOwner = &owner
+				break
+			}
+		}
+		if otherOwner != nil {
+			conflict("executions were already imported as part of another trade", otherOwner)
+			continue
+		}
+
+		if existing == nil {
+			id, err := insertTrade(ctx, tx, trade)
+			if err != nil {
+				return nil, err
+			}
+			if err := tagTrade(ctx, tx, id, trade.UserID, trade.Tags); err != nil {
+				return nil, err
+			}
+			result.Inserted++
+			result.TradeIDs = append(result.TradeIDs, id)
+			continue
+		}
+
+		newExecutions := make([]models.Execution, 0)
+		for _, e := range trade.Executions {
+			if _, known := owners[e.Fingerprint]; !known {
+				newExecutions = append(newExecutions, e)
+			}
+		}
+
+		changed := len(newExecutions) > 0
+		if len(trade.Executions) == 0 {
+			changed = !sameTradeOutcome(existing, trade)
+		}
+
+		if !changed {
+			result.Duplicates++
+			continue
+		}
+
+		if opts.OnDuplicate != DuplicateUpdate && !trade.Continued {
+			conflict("trade was already imported with different executions", &existing.ID)
+			continue
+		}
+
+		if err := updateImportedTrade(ctx, tx, existing.ID, trade, newExecutions); err != nil {
+			return nil, err
+		}
+		if err := tagTrade(ctx, tx, existing.ID, trade.UserID, trade.Tags); err != nil {
+			return nil, err
+		}
+		result.Updated++
+		result.TradeIDs = append(result.TradeIDs, existing.ID)
+	}
+
+	if err := insertImportedCash(ctx, tx, accounts, opts, batch, result); err != nil {
+		return nil, err
+	}
+
+	// A dry run leaves the transaction to be rolled back
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if err := finishImportBatch(ctx, tx, batch.ID, result); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+func cloneTrades(trades []models.Trade) []models.Trade {
+	clones := make([]models.Trade, len(trades))
+	for i, trade := range trades {
+		trade.Executions = append([]models.Execution(nil), trade.Executions...)
+		trade.Orders = append([]models.OrderEvent(nil), trade.Orders...)
+		clones[i] = trade
+	}
+	return clones
+}
+
+// assignImportBatch tags a trade and its executions with the batch importing them
+func assignImportBatch(trade *models.Trade, batchID uuid.UUID) {
+	trade.ImportBatchID = &batchID
+	for i := range trade.Executions {
+		trade.Executions[i].ImportBatchID = &batchID
+	}
+}
+
+// assignFingerprints fills in any missing execution and trade fingerprints
+func assignFingerprints(trade *models.Trade) {
+	for i := range trade.Executions {
+		if trade.Executions[i].Fingerprint == "" {
+			trade.Executions[i].Fingerprint = trade.Executions[i].ComputeFingerprint()
+		}
+	}
+	if trade.Fingerprint == "" {
+		trade.Fingerprint = trade.ComputeFingerprint()
+	}
+}
+
+// findImportedTrade looks up a trade by fingerprint; it returns nil when there is none
+func findImportedTrade(ctx context.Context, tx *sql.Tx, userID uuid.UUID, fingerprint string) (*models.Trade, error) {
+	query := `
+		SELECT id, quantity, exit_price, fees, closed_at
+		FROM trades
+		WHERE user_id = $1 AND fingerprint = $2`
+
+	var trade models.Trade
+	err := tx.QueryRowContext(ctx, query, userID, fingerprint).Scan(
+		&trade.ID, &trade.Quantity, &trade.ExitPrice, &trade.Fees, &trade.ClosedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up imported trade: %w", err)
+	}
+
+	return &trade, nil
+}
+
+// executionOwners maps the fingerprints of already imported executions to the trade that holds them
+func executionOwners(ctx context.Context, tx *sql.Tx, userID uuid.UUID, executions []models.Execution) (map[string]uuid.UUID, error) {
+	owners := make(map[string]uuid.UUID)
+	if len(executions) == 0 {
+		return owners, nil
+	}
+
+	fingerprints := make([]string, 0, len(executions))
+	for _, e := range executions {
+		fingerprints = append(fingerprints, e.Fingerprint)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT fingerprint, trade_id
+		FROM executions
+		WHERE user_id = $1 AND fingerprint = ANY($2)`,
+		userID, pq.Array(fingerprints),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up imported executions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fingerprint string
+		var tradeID uuid.UUID
+		if err := rows.Scan(&fingerprint, &tradeID); err != nil {
+			return nil, fmt.Errorf("failed to scan imported execution: %w", err)
+		}
+		owners[fingerprint] = tradeID
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating imported executions: %w", err)
+	}
+
+	return owners, nil
+}
+
+// insertTrade inserts a trade with its executions and orders inside an existing transaction
+func insertTrade(ctx context.Context, tx *sql.Tx, trade *models.Trade) (uuid.UUID, error) {
+	stmt := `
+		INSERT INTO trades (
+			user_id, symbol, trade_type, quantity, entry_price, exit_price,
+			fees, opened_at, closed_at, multiplier, broker_pnl, pnl_from_broker, currency, account_id, fingerprint, import_batch_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id`
+
+	var id uuid.UUID
+	err := tx.QueryRowContext(
+		ctx,
+		stmt,
+		trade.UserID, trade.Symbol, trade.TradeType, trade.Quantity,
+		trade.EntryPrice, trade.ExitPrice, trade.Fees, trade.OpenedAt, trade.ClosedAt,
+		trade.ContractMultiplier(), trade.BrokerPnL, trade.PnLFromBroker, trade.CurrencyCode(), trade.AccountID, trade.Fingerprint, trade.ImportBatchID,
+	).Scan(&id)
+
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to insert trade: %w", err)
+	}
+
+	if err := insertExecutions(ctx, tx, id, trade.UserID, trade.Executions); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := insertOrderEvents(ctx, tx, id, trade.UserID, trade.Orders); err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+// updateImportedTrade adds new executions and order events to a previously
+// imported trade and recalculates it from the full set of executions. Trades
+// imported without executions take the incoming values as they are.
+func updateImportedTrade(ctx context.Context, tx *sql.Tx, id uuid.UUID, trade *models.Trade, newExecutions []models.Execution) error {
+	if err := insertNewOrderEvents(ctx, tx, id, trade.UserID, trade.Orders); err != nil {
+		return err
+	}
+
+	if len(trade.Executions) > 0 {
+		if err := insertExecutions(ctx, tx, id, trade.UserID, newExecutions); err != nil {
+			return err
+		}
+		return rebuildTrade(ctx, tx, id, trade.UserID)
+	}
+
+	return saveTradeFields(ctx, tx, id, trade)
+}
+
+// rebuildTrade recalculates a trade from its stored executions inside an existing transaction
+func rebuildTrade(ctx context.Context, tx *sql.Tx, id, userID uuid.UUID) error {
+	executions, err := listExecutions(ctx, tx, id, userID)
+	if err != nil {
+		return err
+	}
+	if len(executions) == 0 {
+		return nil
+	}
+
+	rebuilt := models.Trade{UserID: userID, Executions: executions}
+	rebuilt.RebuildFromExecutions()
+
+	return saveTradeFields(ctx, tx, id, &rebuilt)
+}
+
+func saveTradeFields(ctx context.Context, tx *sql.Tx, id uuid.UUID, trade *models.Trade) error {
+	query := `
+		UPDATE trades
+		SET symbol = $3, trade_type = $4, quantity = $5, entry_price = $6,
+		    exit_price = $7, fees = $8, opened_at = $9, closed_at = $10,
+		    multiplier = $11, broker_pnl = $12, pnl_from_broker = $13
+		WHERE id = $1 AND user_id = $2`
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		id, trade.UserID, trade.Symbol, trade.TradeType, trade.Quantity,
+		trade.EntryPrice, trade.ExitPrice, trade.Fees, trade.OpenedAt, trade.ClosedAt,
+		trade.ContractMultiplier(), trade.BrokerPnL, trade.PnLFromBroker,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update imported trade: %w", err)
+	}
+
+	return nil
+}
+
+// tagTrade attaches tags to a trade by name, creating any the user does not have yet
+func tagTrade(ctx context.Context, tx *sql.Tx, tradeID, userID uuid.UUID, names []string) error {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+
+		var tagID uuid.UUID
+		err := tx.QueryRowContext(ctx, `
+			SELECT id FROM tags WHERE user_id = $1 AND LOWER(name) = LOWER($2)`,
+			userID, name,
+		).Scan(&tagID)
+
+		if err == sql.ErrNoRows {
+			err = tx.QueryRowContext(ctx, `
+				INSERT INTO tags (user_id, name) VALUES ($1, $2)
+				RETURNING id`,
+				userID, name,
+			).Scan(&tagID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to resolve tag %q: %w", name, err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO trade_tags (trade_id, tag_id)
+			VALUES ($1, $2)
+			ON CONFLICT (trade_id, tag_id) DO NOTHING`,
+			tradeID, tagID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to add tag to trade: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ListOpenImportedTrades retrieves the open trades first imported from a source,
+// with their executions. Broker syncs seed their position engine with them so
+// fills closing an overnight hold continue the same trade.
+func (db *DB) ListOpenImportedTrades(ctx context.Context, userID uuid.UUID, source models.ImportSource) ([]models.Trade, error) {
+	query := `
+		SELECT
+			t.id, t.user_id, t.symbol, t.trade_type, t.quantity, t.entry_price,
+			t.fees, t.opened_at, t.fingerprint, t.import_batch_id
+		FROM trades t
+		JOIN import_batches b ON b.id = t.import_batch_id
+		WHERE t.user_id = $1 AND b.source = $2 AND t.exit_price IS NULL
+		ORDER BY t.opened_at ASC`
+
+	rows, err := db.QueryContext(ctx, query, userID, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open imported trades: %w", err)
+	}
+	defer rows.Close()
+
+	trades := make([]models.Trade, 0)
+	for rows.Next() {
+		var trade models.Trade
+		err := rows.Scan(
+			&trade.ID, &trade.UserID, &trade.Symbol, &trade.TradeType, &trade.Quantity, &trade.EntryPrice,
+			&trade.Fees, &trade.OpenedAt, &trade.Fingerprint, &trade.ImportBatchID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan open imported trade: %w", err)
+		}
+		trades = append(trades, trade)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating open imported trades: %w", err)
+	}
+
+	for i := range trades {
+		trades[i].Executions, err = listExecutions(ctx, db, trades[i].ID, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return trades, nil
+}
+
+// sameTradeOutcome compares the fields a re-import of a trade without executions can change
+func sameTradeOutcome(existing, incoming *models.Trade) bool {
+	if !floatsEqual(existing.Quantity, incoming.Quantity) || !floatsEqual(existing.Fees, incoming.Fees) {
+		return false
+	}
+	if (existing.ExitPrice == nil) != (incoming.ExitPrice == nil) {
+		return false
+	}
+	if existing.ExitPrice != nil && !floatsEqual(*existing.ExitPrice, *incoming.ExitPrice) {
+		return false
+	}
+	if (existing.ClosedAt == nil) != (incoming.ClosedAt == nil) {
+		return false
+	}
+	if existing.ClosedAt != nil && !existing.ClosedAt.Equal(*incoming.ClosedAt) {
+		return false
+	}
+	return true
+}
+
+func floatsEqual(a, b float64) bool {
+	// Stored as DECIMAL(18, 8)
+	return math.Abs(a-b) < 1e-8
+}
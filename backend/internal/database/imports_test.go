@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/importers"
+	"github.com/tradepulse/api/internal/models"
+)
+
+// importFile builds trades from a file's executions and order events,
+// continuing the positions earlier imports left open, and imports them in the
+// default duplicate mode
+func importFile(t *testing.T, db *DB, userID uuid.UUID, executions []models.Execution, orders ...models.OrderEvent) *ImportResult {
+	t.Helper()
+	ctx := context.Background()
+
+	open, err := db.ListOpenImportedTrades(ctx, userID, models.ImportSourceDAS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trades := importers.BuildTrades(executions, open)
+	if unlinked := importers.LinkOrders(trades, orders); len(unlinked) != 0 {
+		t.Fatalf("%d orders were not linked to a trade", len(unlinked))
+	}
+	for i := range trades {
+		trades[i].UserID = userID
+	}
+
+	mode, err := ParseDuplicateMode("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := db.BulkCreateTrades(ctx, trades, ImportOptions{OnDuplicate: mode, Source: models.ImportSourceDAS})
+	if err != nil {
+		t.Fatalf("BulkCreateTrades: %v", err)
+	}
+	return result
+}
+
+func TestImportClosesPositionLeftOpen(t *testing.T) {
+	db := openTestDB(t)
+	userID := createTestUser(t, db)
+
+	opened := time.Date(2025, 11, 10, 15, 55, 0, 0, time.UTC)
+	fill := func(side models.ExecutionSide, price float64, at time.Time, fillID string) models.Execution {
+		return models.Execution{
+			Account: "TEST1", Symbol: "AAPL", Side: side, Quantity: 100, Price: price, ExecutedAt: at, FillID: fillID,
+		}
+	}
+
+	// File A opens the position and file B, the next day, closes it
+	first := importFile(t, db, userID, []models.Execution{fill(models.SideBuy, 230, opened, "A1")})
+	if first.Inserted != 1 || len(first.TradeIDs) != 1 {
+		t.Fatalf("file A inserted %d trades, want 1", first.Inserted)
+	}
+
+	closed := opened.Add(18 * time.Hour)
+	canceled := models.OrderEvent{
+		Account: "TEST1", Symbol: "AAPL", Side: models.SideSell, Quantity: 100, Price: 235,
+		Status: models.OrderCanceled, EventAt: closed.Add(-time.Hour),
+	}
+	second := importFile(t, db, userID, []models.Execution{fill(models.SideSell, 232, closed, "B1")}, canceled)
+	if second.Updated != 1 || second.Inserted != 0 || second.Conflicts != 0 {
+		t.Fatalf("file B = %d updated, %d inserted, %d conflicts (%+v); want the open trade updated",
+			second.Updated, second.Inserted, second.Conflicts, second.Conflicted)
+	}
+	if second.TradeIDs[0] != first.TradeIDs[0] {
+		t.Errorf("file B updated trade %s, want %s", second.TradeIDs[0], first.TradeIDs[0])
+	}
+
+	trade, err := db.GetTrade(context.Background(), first.TradeIDs[0], userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trade.ExitPrice == nil || *trade.ExitPrice != 232 || trade.ClosedAt == nil || len(trade.Executions) != 2 {
+		t.Errorf("trade = exit %v, closed %v, %d executions; want closed at 232 with 2 executions",
+			trade.ExitPrice, trade.ClosedAt, len(trade.Executions))
+	}
+
+	orders, err := db.ListOrderEventsByTradeID(context.Background(), trade.ID, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 1 || orders[0].Price != 235 {
+		t.Errorf("order events = %+v, want file B's canceled order", orders)
+	}
+}
+
+func TestUpdatedTradeKeepsOrderEventsOnce(t *testing.T) {
+	db := openTestDB(t)
+	userID := createTestUser(t, db)
+	ctx := context.Background()
+
+	opened := time.Date(2025, 11, 12, 14, 30, 0, 0, time.UTC)
+	trade := models.Trade{
+		UserID: userID,
+		Symbol: "MSFT",
+		Executions: []models.Execution{
+			{Account: "TEST1", Symbol: "MSFT", Side: models.SideBuy, Quantity: 10, Price: 500, ExecutedAt: opened, FillID: "M1"},
+		},
+	}
+	trade.RebuildFromExecutions()
+
+	if _, err := db.BulkCreateTrades(ctx, []models.Trade{trade}, ImportOptions{Source: models.ImportSourceDAS}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two later imports each add a fill and carry the same rejected order
+	trade.Orders = []models.OrderEvent{{
+		Account: "TEST1", Symbol: "MSFT", Side: models.SideBuy, Quantity: 10, Price: 499.5,
+		Status: models.OrderRejected, EventAt: opened.Add(time.Minute),
+	}}
+	var tradeID uuid.UUID
+	for n, fillID := range []string{"M2", "M3"} {
+		trade.Executions = append(trade.Executions, models.Execution{
+			Account: "TEST1", Symbol: "MSFT", Side: models.SideBuy, Quantity: 5, Price: 503,
+			ExecutedAt: opened.Add(time.Duration(n+1) * time.Hour), FillID: fillID,
+		})
+		result, err := db.BulkCreateTrades(ctx, []models.Trade{trade}, ImportOptions{OnDuplicate: DuplicateUpdate, Source: models.ImportSourceDAS})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Updated != 1 {
+			t.Fatalf("import %d updated %d trades, want 1", n+2, result.Updated)
+		}
+		tradeID = result.TradeIDs[0]
+	}
+
+	orders, err := db.ListOrderEventsByTradeID(ctx, tradeID, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 1 {
+		t.Errorf("order events = %d, want 1", len(orders))
+	}
+}
@@ -0,0 +1,267 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/tradepulse/api/internal/models"
+)
+
+const connectionColumns = `
+	id, user_id, provider, name, site, username, account_id, encrypted_credentials,
+	last_sync_status, last_sync_error, last_sync_at, last_sync_job_id, sync_enabled, sync_weekdays,
+	sync_time, sync_timezone, synced_through, sync_failures, next_sync_at, created_at, updated_at`
+
+// ListIntegrationConnections retrieves all broker connections for a user
+func (db *DB) ListIntegrationConnections(ctx context.Context, userID uuid.UUID) ([]models.IntegrationConnection, error) {
+	query := `SELECT ` + connectionColumns + `
+		FROM integration_connections
+		WHERE user_id = $1
+		ORDER BY name ASC`
+
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list integration connections: %w", err)
+	}
+	defer rows.Close()
+
+	// Initialize to empty slice to avoid null JSON serialization
+	connections := make([]models.IntegrationConnection, 0)
+	for rows.Next() {
+		connection, err := scanIntegrationConnection(rows)
+		if err != nil {
+			return nil, err
+		}
+		connections = append(connections, *connection)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating integration connections: %w", err)
+	}
+
+	return connections, nil
+}
+
+// GetIntegrationConnection retrieves a single broker connection by ID
+func (db *DB) GetIntegrationConnection(ctx context.Context, id, userID uuid.UUID) (*models.IntegrationConnection, error) {
+	query := `SELECT ` + connectionColumns + `
+		FROM integration_connections
+		WHERE id = $1 AND user_id = $2`
+
+	connection, err := scanIntegrationConnection(db.QueryRowContext(ctx, query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return connection, nil
+}
+
+// CreateIntegrationConnection inserts a new broker connection
+func (db *DB) CreateIntegrationConnection(ctx context.Context, connection *models.IntegrationConnection) error {
+	query := `
+		INSERT INTO integration_connections (
+			user_id, provider, name, site, username, account_id, encrypted_credentials,
+			sync_enabled, sync_weekdays, sync_time, sync_timezone, synced_through, next_sync_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id, created_at, updated_at`
+
+	err := db.QueryRowContext(
+		ctx,
+		query,
+		connection.UserID, connection.Provider, connection.Name, connection.Site,
+		connection.Username, connection.AccountID, connection.EncryptedCredentials,
+		connection.SyncEnabled, pq.Array(weekdays(connection.SyncWeekdays)), connection.SyncTime,
+		connection.SyncTimezone, connection.SyncedThrough, connection.NextSyncAt,
+	).Scan(&connection.ID, &connection.CreatedAt, &connection.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create integration connection: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateIntegrationConnection updates a broker connection's settings, credentials and schedule
+func (db *DB) UpdateIntegrationConnection(ctx context.Context, id, userID uuid.UUID, connection *models.IntegrationConnection) error {
+	query := `
+		UPDATE integration_connections
+		SET name = $3, site = $4, username = $5, account_id = $6, encrypted_credentials = $7,
+		    sync_enabled = $8, sync_weekdays = $9, sync_time = $10, sync_timezone = $11,
+		    synced_through = $12, next_sync_at = $13
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + connectionColumns
+
+	updated, err := scanIntegrationConnection(db.QueryRowContext(
+		ctx,
+		query,
+		id, userID, connection.Name, connection.Site, connection.Username,
+		connection.AccountID, connection.EncryptedCredentials,
+		connection.SyncEnabled, pq.Array(weekdays(connection.SyncWeekdays)), connection.SyncTime,
+		connection.SyncTimezone, connection.SyncedThrough, connection.NextSyncAt,
+	))
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("integration connection not found or unauthorized")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update integration connection: %w", err)
+	}
+
+	*connection = *updated
+	return nil
+}
+
+// DeleteIntegrationConnection deletes a broker connection
+func (db *DB) DeleteIntegrationConnection(ctx context.Context, id, userID uuid.UUID) error {
+	result, err := db.ExecContext(ctx, `DELETE FROM integration_connections WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete integration connection: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("integration connection not found or unauthorized")
+	}
+
+	return nil
+}
+
+// UpdateConnectionSyncStatus records the state of a connection's latest sync
+func (db *DB) UpdateConnectionSyncStatus(ctx context.Context, id uuid.UUID, jobID *uuid.UUID, status models.SyncStatus, syncErr string) error {
+	query := `
+		UPDATE integration_connections
+		SET last_sync_status = $2, last_sync_error = $3, last_sync_job_id = COALESCE($4, last_sync_job_id),
+		    last_sync_at = CASE WHEN $2 = 'RUNNING' THEN last_sync_at ELSE NOW() END
+		WHERE id = $1`
+
+	if _, err := db.ExecContext(ctx, query, id, status, syncErr, jobID); err != nil {
+		return fmt.Errorf("failed to update connection sync status: %w", err)
+	}
+
+	return nil
+}
+
+// ListDueSyncs returns enabled connections whose scheduled sync is due and not already running
+func (db *DB) ListDueSyncs(ctx context.Context, now time.Time) ([]models.IntegrationConnection, error) {
+	query := `SELECT ` + connectionColumns + `
+		FROM integration_connections
+		WHERE sync_enabled AND next_sync_at <= $1 AND last_sync_status <> 'RUNNING'
+		ORDER BY next_sync_at ASC`
+
+	rows, err := db.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due syncs: %w", err)
+	}
+	defer rows.Close()
+
+	// Initialize to empty slice to avoid null JSON serialization
+	connections := make([]models.IntegrationConnection, 0)
+	for rows.Next() {
+		connection, err := scanIntegrationConnection(rows)
+		if err != nil {
+			return nil, err
+		}
+		connections = append(connections, *connection)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating due syncs: %w", err)
+	}
+
+	return connections, nil
+}
+
+// ClaimScheduledSync moves a due connection's next_sync_at from due to next. It
+// reports false when another scheduler instance claimed the run first.
+func (db *DB) ClaimScheduledSync(ctx context.Context, id uuid.UUID, due, next time.Time) (bool, error) {
+	result, err := db.ExecContext(ctx, `
+		UPDATE integration_connections
+		SET next_sync_at = $3
+		WHERE id = $1 AND sync_enabled AND next_sync_at = $2`,
+		id, due, next,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim scheduled sync: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// RecordScheduledSync stores the outcome of a scheduled sync. through, when not
+// empty, advances the high-water mark. A non-nil retryAt counts a failure and
+// brings the next run forward to retryAt unless the regular schedule comes sooner;
+// a nil retryAt clears the failure count.
+func (db *DB) RecordScheduledSync(ctx context.Context, id uuid.UUID, through string, retryAt *time.Time) error {
+	var throughDate *string
+	if through != "" {
+		throughDate = &through
+	}
+
+	query := `
+		UPDATE integration_connections
+		SET synced_through = CASE
+		        WHEN $2::date IS NULL THEN synced_through
+		        ELSE GREATEST(COALESCE(synced_through, $2::date), $2::date)
+		    END,
+		    sync_failures = CASE WHEN $3::timestamptz IS NULL THEN 0 ELSE sync_failures + 1 END,
+		    next_sync_at = CASE
+		        WHEN $3::timestamptz IS NULL OR NOT sync_enabled THEN next_sync_at
+		        ELSE LEAST(COALESCE(next_sync_at, $3::timestamptz), $3::timestamptz)
+		    END
+		WHERE id = $1`
+
+	if _, err := db.ExecContext(ctx, query, id, throughDate, retryAt); err != nil {
+		return fmt.Errorf("failed to record scheduled sync: %w", err)
+	}
+
+	return nil
+}
+
+func scanIntegrationConnection(row rowScanner) (*models.IntegrationConnection, error) {
+	var connection models.IntegrationConnection
+	var syncWeekdays pq.Int64Array
+
+	err := row.Scan(
+		&connection.ID, &connection.UserID, &connection.Provider, &connection.Name,
+		&connection.Site, &connection.Username, &connection.AccountID, &connection.EncryptedCredentials,
+		&connection.LastSyncStatus, &connection.LastSyncError, &connection.LastSyncAt,
+		&connection.LastSyncJobID, &connection.SyncEnabled, &syncWeekdays,
+		&connection.SyncTime, &connection.SyncTimezone, &connection.SyncedThrough,
+		&connection.SyncFailures, &connection.NextSyncAt, &connection.CreatedAt, &connection.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan integration connection: %w", err)
+	}
+
+	connection.SyncWeekdays = make([]int, len(syncWeekdays))
+	for i, day := range syncWeekdays {
+		connection.SyncWeekdays[i] = int(day)
+	}
+
+	return &connection, nil
+}
+
+func weekdays(days []int) []int64 {
+	values := make([]int64, len(days))
+	for i, day := range days {
+		values[i] = int64(day)
+	}
+	return values
+}
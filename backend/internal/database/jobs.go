@@ -0,0 +1,203 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/models"
+)
+
+const jobColumns = `
+	id, user_id, type, status, payload, result, error, progress_current,
+	progress_total, progress_message, started_at, finished_at, created_at, updated_at`
+
+// CreateJob queues a new job
+func (db *DB) CreateJob(ctx context.Context, job *models.Job) error {
+	payload := job.Payload
+	if payload == nil {
+		payload = json.RawMessage("{}")
+	}
+
+	query := `
+		INSERT INTO jobs (user_id, type, payload)
+		VALUES ($1, $2, $3)
+		RETURNING ` + jobColumns
+
+	created, err := scanJob(db.QueryRowContext(ctx, query, job.UserID, job.Type, []byte(payload)))
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	created.Secret = job.Secret
+	*job = *created
+	return nil
+}
+
+// GetJob retrieves a single job by ID
+func (db *DB) GetJob(ctx context.Context, id, userID uuid.UUID) (*models.Job, error) {
+	query := `SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE id = $1 AND user_id = $2`
+
+	job, err := scanJob(db.QueryRowContext(ctx, query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// ListJobs retrieves a user's most recent jobs
+func (db *DB) ListJobs(ctx context.Context, userID uuid.UUID, limit int) ([]models.Job, error) {
+	query := `SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	rows, err := db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	// Initialize to empty slice to avoid null JSON serialization
+	jobs := make([]models.Job, 0)
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// ClaimNextJob marks the oldest queued job as running and returns it, or nil
+// when the queue is empty. Concurrent workers never claim the same job.
+func (db *DB) ClaimNextJob(ctx context.Context) (*models.Job, error) {
+	query := `
+		UPDATE jobs
+		SET status = $1, started_at = NOW()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE status = $2
+			ORDER BY created_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(db.QueryRowContext(ctx, query, models.JobRunning, models.JobQueued))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// UpdateJobProgress records how far a running job has got
+func (db *DB) UpdateJobProgress(ctx context.Context, id uuid.UUID, current, total int, message string) error {
+	query := `
+		UPDATE jobs
+		SET progress_current = $2, progress_total = $3, progress_message = $4
+		WHERE id = $1`
+
+	if _, err := db.ExecContext(ctx, query, id, current, total, message); err != nil {
+		return fmt.Errorf("failed to update job progress: %w", err)
+	}
+
+	return nil
+}
+
+// CompleteJob marks a job as finished and stores its result
+func (db *DB) CompleteJob(ctx context.Context, id uuid.UUID, result json.RawMessage) error {
+	query := `
+		UPDATE jobs
+		SET status = $2, result = $3, finished_at = NOW()
+		WHERE id = $1`
+
+	if _, err := db.ExecContext(ctx, query, id, models.JobCompleted, []byte(result)); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	return nil
+}
+
+// FailJob marks a job as failed with the reason
+func (db *DB) FailJob(ctx context.Context, id uuid.UUID, reason string) error {
+	query := `
+		UPDATE jobs
+		SET status = $2, error = $3, finished_at = NOW()
+		WHERE id = $1`
+
+	if _, err := db.ExecContext(ctx, query, id, models.JobFailed, reason); err != nil {
+		return fmt.Errorf("failed to mark job as failed: %w", err)
+	}
+
+	return nil
+}
+
+// FailInterruptedJobs fails jobs left running by a previous process, returning
+// how many there were. Broker connections whose sync was running are marked as
+// failed with them, or the scheduler would wait on those syncs forever.
+func (db *DB) FailInterruptedJobs(ctx context.Context) (int, error) {
+	query := `
+		WITH failed AS (
+			UPDATE jobs
+			SET status = $1, error = 'interrupted by a server restart', finished_at = NOW()
+			WHERE status = $2
+			RETURNING id
+		), reset AS (
+			UPDATE integration_connections
+			SET last_sync_status = $3, last_sync_error = 'interrupted by a server restart', last_sync_at = NOW()
+			WHERE last_sync_status = $4
+		)
+		SELECT COUNT(*) FROM failed`
+
+	var count int
+	err := db.QueryRowContext(ctx, query, models.JobFailed, models.JobRunning, models.SyncFailed, models.SyncRunning).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail interrupted jobs: %w", err)
+	}
+
+	return count, nil
+}
+
+func scanJob(row rowScanner) (*models.Job, error) {
+	var job models.Job
+	var payload, result []byte
+
+	err := row.Scan(
+		&job.ID, &job.UserID, &job.Type, &job.Status, &payload, &result, &job.Error,
+		&job.ProgressCurrent, &job.ProgressTotal, &job.ProgressMessage,
+		&job.StartedAt, &job.FinishedAt, &job.CreatedAt, &job.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan job: %w", err)
+	}
+
+	job.Payload = payload
+	if result != nil {
+		job.Result = result
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/models"
+)
+
+// ListTradeResults retrieves the outcome fields of every trade matching the
+// filters, oldest close first, for computing performance metrics. Tags,
+// executions and journal flags are not loaded.
+func (db *DB) ListTradeResults(ctx context.Context, userID uuid.UUID, filters TradeFilters) ([]models.Trade, error) {
+	query := `
+		SELECT
+			t.id, t.symbol, t.trade_type, t.quantity, t.entry_price, t.exit_price,
+			t.fees, t.pnl, t.multiplier, t.currency, t.account_id, t.opened_at, t.closed_at
+		FROM trades t
+		WHERE t.user_id = $1`
+
+	filterClause, args := tradeFilterClause(filters, []interface{}{userID})
+	query += filterClause + " ORDER BY t.closed_at ASC NULLS LAST, t.opened_at ASC"
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trade results: %w", err)
+	}
+	defer rows.Close()
+
+	// Initialize to empty slice to avoid null JSON serialization
+	trades := make([]models.Trade, 0)
+	for rows.Next() {
+		trade := models.Trade{UserID: userID}
+		err := rows.Scan(
+			&trade.ID, &trade.Symbol, &trade.TradeType, &trade.Quantity, &trade.EntryPrice, &trade.ExitPrice,
+			&trade.Fees, &trade.PnL, &trade.Multiplier, &trade.Currency, &trade.AccountID, &trade.OpenedAt, &trade.ClosedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trade result: %w", err)
+		}
+		trades = append(trades, trade)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trade results: %w", err)
+	}
+
+	return trades, nil
+}
+
+// SymbolMetrics are the aggregate results of the closed trades in one symbol.
+// Average R covers only the trades with a stop loss away from the entry price,
+// which RTradeCount counts; it and the profit factor are nil when there is
+// nothing to compute them from.
+type SymbolMetrics struct {
+	Symbol             string   `json:"symbol"`
+	TradeCount         int      `json:"trade_count"`
+	WinCount           int      `json:"win_count"`
+	LossCount          int      `json:"loss_count"`
+	WinRate            float64  `json:"win_rate"`
+	NetPnL             float64  `json:"net_pnl"`
+	AveragePnL         float64  `json:"average_pnl"`
+	TotalFees          float64  `json:"total_fees"`
+	ProfitFactor       *float64 `json:"profit_factor"`
+	AverageR           *float64 `json:"average_r"`
+	RTradeCount        int      `json:"r_trade_count"`
+	AverageHoldSeconds float64  `json:"average_hold_seconds"`
+}
+
+// symbolMetricsSorts are the columns symbol metrics can be sorted by
+var symbolMetricsSorts = map[string]bool{
+	"symbol":               true,
+	"trade_count":          true,
+	"win_rate":             true,
+	"net_pnl":              true,
+	"average_pnl":          true,
+	"total_fees":           true,
+	"profit_factor":        true,
+	"average_r":            true,
+	"average_hold_seconds": true,
+}
+
+// SymbolMetricsOptions controls the grouping query of ListSymbolMetrics
+type SymbolMetricsOptions struct {
+	SortBy     string // one of the SymbolMetrics JSON names; default net_pnl
+	Descending bool
+	MinTrades  int // symbols with fewer closed trades are left out
+}
+
+// ValidSymbolMetricsSort reports whether symbol metrics can be sorted by the column
+func ValidSymbolMetricsSort(column string) bool {
+	return symbolMetricsSorts[column]
+}
+
+// ListSymbolMetrics aggregates the closed trades matching the filters by symbol.
+// R is the net P&L over the amount risked: the distance from entry to stop loss
+// times the quantity and contract multiplier.
+func (db *DB) ListSymbolMetrics(ctx context.Context, userID uuid.UUID, filters TradeFilters, opts SymbolMetricsOptions) ([]SymbolMetrics, error) {
+	sortBy := opts.SortBy
+	if sortBy == "" {
+		sortBy = "net_pnl"
+	}
+	if !symbolMetricsSorts[sortBy] {
+		return nil, fmt.Errorf("invalid sort column %q", sortBy)
+	}
+	direction := "ASC"
+	if opts.Descending {
+		direction = "DESC"
+	}
+
+	query := `
+		SELECT
+			UPPER(t.symbol) AS symbol,
+			COUNT(*) AS trade_count,
+			COUNT(*) FILTER (WHERE t.pnl > 0) AS win_count,
+			COUNT(*) FILTER (WHERE t.pnl < 0) AS loss_count,
+			100.0 * COUNT(*) FILTER (WHERE t.pnl > 0) / COUNT(*) AS win_rate,
+			SUM(t.pnl) AS net_pnl,
+			AVG(t.pnl) AS average_pnl,
+			SUM(t.fees) AS total_fees,
+			COALESCE(SUM(t.pnl) FILTER (WHERE t.pnl > 0), 0)
+				/ NULLIF(-SUM(t.pnl) FILTER (WHERE t.pnl < 0), 0) AS profit_factor,
+			AVG(t.pnl / NULLIF(ABS(t.entry_price - t.stop_loss) * t.quantity * t.multiplier, 0)) AS average_r,
+			COUNT(NULLIF(ABS(t.entry_price - t.stop_loss) * t.quantity * t.multiplier, 0)) AS r_trade_count,
+			AVG(EXTRACT(EPOCH FROM (t.closed_at - t.opened_at))) AS average_hold_seconds
+		FROM trades t
+		WHERE t.user_id = $1 AND t.pnl IS NOT NULL AND t.closed_at IS NOT NULL`
+
+	filterClause, args := tradeFilterClause(filters, []interface{}{userID})
+	query += filterClause
+
+	args = append(args, opts.MinTrades)
+	query += fmt.Sprintf(`
+		GROUP BY UPPER(t.symbol)
+		HAVING COUNT(*) >= $%d
+		ORDER BY %s %s NULLS LAST, symbol ASC`, len(args), sortBy, direction)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate symbol metrics: %w", err)
+	}
+	defer rows.Close()
+
+	// Initialize to empty slice to avoid null JSON serialization
+	results := make([]SymbolMetrics, 0)
+	for rows.Next() {
+		var m SymbolMetrics
+		err := rows.Scan(
+			&m.Symbol, &m.TradeCount, &m.WinCount, &m.LossCount, &m.WinRate, &m.NetPnL, &m.AveragePnL,
+			&m.TotalFees, &m.ProfitFactor, &m.AverageR, &m.RTradeCount, &m.AverageHoldSeconds,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan symbol metrics: %w", err)
+		}
+		results = append(results, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating symbol metrics: %w", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/models"
+)
+
+// insertOrderEvents stores the canceled and rejected orders for a trade inside an existing transaction
+func insertOrderEvents(ctx context.Context, tx *sql.Tx, tradeID, userID uuid.UUID, orders []models.OrderEvent) error {
+	stmt := `
+		INSERT INTO order_events (
+			trade_id, user_id, account, symbol, side, quantity, price, route, status, note, event_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at`
+
+	for i := range orders {
+		o := &orders[i]
+		o.TradeID = tradeID
+		o.UserID = userID
+
+		err := tx.QueryRowContext(
+			ctx,
+			stmt,
+			o.TradeID, o.UserID, o.Account, o.Symbol, o.Side, o.Quantity, o.Price, o.Route, o.Status, o.Note, o.EventAt,
+		).Scan(&o.ID, &o.CreatedAt)
+
+		if err != nil {
+			return fmt.Errorf("failed to insert order event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// insertNewOrderEvents stores the orders a trade does not have yet inside an
+// existing transaction. Order events carry no broker ID, so a stored one is
+// recognized by its account, symbol, side, quantity, price, status and time.
+func insertNewOrderEvents(ctx context.Context, tx *sql.Tx, tradeID, userID uuid.UUID, orders []models.OrderEvent) error {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM order_events
+			WHERE trade_id = $1 AND user_id = $2 AND account = $3 AND symbol = $4 AND side = $5
+			  AND quantity = $6 AND price = $7 AND status = $8 AND event_at = $9
+		)`
+
+	newOrders := make([]models.OrderEvent, 0, len(orders))
+	for _, o := range orders {
+		var stored bool
+		err := tx.QueryRowContext(
+			ctx,
+			query,
+			tradeID, userID, o.Account, o.Symbol, o.Side, o.Quantity, o.Price, o.Status, o.EventAt,
+		).Scan(&stored)
+		if err != nil {
+			return fmt.Errorf("failed to look up order event: %w", err)
+		}
+		if !stored {
+			newOrders = append(newOrders, o)
+		}
+	}
+
+	return insertOrderEvents(ctx, tx, tradeID, userID, newOrders)
+}
+
+// ListOrderEventsByTradeID retrieves the canceled and rejected orders linked to a trade
+func (db *DB) ListOrderEventsByTradeID(ctx context.Context, tradeID, userID uuid.UUID) ([]models.OrderEvent, error) {
+	query := `
+		SELECT id, trade_id, user_id, account, symbol, side, quantity, price, route, status, note, event_at, created_at
+		FROM order_events
+		WHERE trade_id = $1 AND user_id = $2
+		ORDER BY event_at ASC`
+
+	rows, err := db.QueryContext(ctx, query, tradeID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order events: %w", err)
+	}
+	defer rows.Close()
+
+	// Initialize to empty slice to avoid null JSON serialization
+	orders := make([]models.OrderEvent, 0)
+	for rows.Next() {
+		var o models.OrderEvent
+		err := rows.Scan(
+			&o.ID, &o.TradeID, &o.UserID, &o.Account, &o.Symbol, &o.Side, &o.Quantity, &o.Price,
+			&o.Route, &o.Status, &o.Note, &o.EventAt, &o.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order event: %w", err)
+		}
+		orders = append(orders, o)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating order events: %w", err)
+	}
+
+	return orders, nil
+}
@@ -12,97 +12,107 @@ import (
 
 // TradeFilters represents filters for listing trades
 type TradeFilters struct {
-	Symbol     string
-	TradeType  string // "LONG" or "SHORT"
-	Status     string // "open", "closed", "all"
-	StartDate  string // ISO 8601 format
-	EndDate    string // ISO 8601 format
-	Strategy   string
-	MinPnL     *float64
-	MaxPnL     *float64
-	Limit      int
-	Offset     int
+	Symbol        string
+	TradeType     string // "LONG" or "SHORT"
+	Status        string // "open", "closed", "all"
+	StartDate     string // ISO 8601 format
+	EndDate       string // ISO 8601 format
+	Strategy      string
+	MinPnL        *float64
+	MaxPnL        *float64
+	ImportBatchID string
+	AccountID     string
+	Limit         int
+	Offset        int
 }
 
-// PaginatedTradesResult represents a paginated list of trades with metadata
-type PaginatedTradesResult struct {
-	Trades     []models.Trade `json:"trades"`
-	Total      int            `json:"total"`
-	Page       int            `json:"page"`
-	PageSize   int            `json:"page_size"`
-	TotalPages int            `json:"total_pages"`
-}
-
-// ListTrades retrieves all trades for a user with optional filters
-func (db *DB) ListTrades(ctx context.Context, userID uuid.UUID, filters TradeFilters) ([]models.Trade, error) {
-	query := `
-		SELECT
-			t.id, t.user_id, t.symbol, t.trade_type, t.quantity,
-			t.entry_price, t.exit_price, t.fees, t.pnl,
-			t.opened_at, t.closed_at, t.created_at, t.updated_at,
-			EXISTS(SELECT 1 FROM journal_entries je WHERE je.trade_id = t.id) as has_journal,
-			COALESCE(
-				(SELECT json_agg(tag.name)
-				FROM trade_tags tt
-				JOIN tags tag ON tt.tag_id = tag.id
-				WHERE tt.trade_id = t.id),
-				'[]'::json
-			) as tags
-		FROM trades t
-		WHERE t.user_id = $1`
+// tradeFilterClause returns the conditions selecting the filtered trades of
+// table alias t, to follow a WHERE clause, with args extended by their values
+func tradeFilterClause(filters TradeFilters, args []interface{}) (string, []interface{}) {
+	var clause strings.Builder
 
-	args := []interface{}{userID}
-	argCount := 1
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		clause.WriteString(" AND ")
+		clause.WriteString(fmt.Sprintf(condition, len(args)))
+	}
 
-	// Apply filters
 	if filters.Symbol != "" {
-		argCount++
-		query += fmt.Sprintf(" AND UPPER(t.symbol) = UPPER($%d)", argCount)
-		args = append(args, filters.Symbol)
+		add("UPPER(t.symbol) = UPPER($%d)", filters.Symbol)
 	}
 
 	if filters.TradeType != "" {
-		argCount++
-		query += fmt.Sprintf(" AND t.trade_type = $%d", argCount)
-		args = append(args, filters.TradeType)
+		add("t.trade_type = $%d", filters.TradeType)
 	}
 
 	if filters.Status == "open" {
-		query += " AND t.exit_price IS NULL"
+		clause.WriteString(" AND t.exit_price IS NULL")
 	} else if filters.Status == "closed" {
-		query += " AND t.exit_price IS NOT NULL"
+		clause.WriteString(" AND t.exit_price IS NOT NULL")
 	}
 
 	if filters.StartDate != "" {
-		argCount++
-		query += fmt.Sprintf(" AND t.opened_at >= $%d", argCount)
-		args = append(args, filters.StartDate)
+		add("t.opened_at >= $%d", filters.StartDate)
 	}
 
 	if filters.EndDate != "" {
-		argCount++
-		query += fmt.Sprintf(" AND t.opened_at <= $%d", argCount)
-		args = append(args, filters.EndDate)
+		add("t.opened_at <= $%d", filters.EndDate)
 	}
 
 	if filters.Strategy != "" {
-		argCount++
-		query += fmt.Sprintf(" AND t.strategy = $%d", argCount)
-		args = append(args, filters.Strategy)
+		add("t.strategy = $%d", filters.Strategy)
 	}
 
 	if filters.MinPnL != nil {
-		argCount++
-		query += fmt.Sprintf(" AND t.pnl >= $%d", argCount)
-		args = append(args, *filters.MinPnL)
+		add("t.pnl >= $%d", *filters.MinPnL)
 	}
 
 	if filters.MaxPnL != nil {
-		argCount++
-		query += fmt.Sprintf(" AND t.pnl <= $%d", argCount)
-		args = append(args, *filters.MaxPnL)
+		add("t.pnl <= $%d", *filters.MaxPnL)
+	}
+
+	if filters.ImportBatchID != "" {
+		add("t.import_batch_id = $%d", filters.ImportBatchID)
 	}
 
+	if filters.AccountID != "" {
+		add("t.account_id = $%d", filters.AccountID)
+	}
+
+	return clause.String(), args
+}
+
+// PaginatedTradesResult represents a paginated list of trades with metadata
+type PaginatedTradesResult struct {
+	Trades     []models.Trade `json:"trades"`
+	Total      int            `json:"total"`
+	Page       int            `json:"page"`
+	PageSize   int            `json:"page_size"`
+	TotalPages int            `json:"total_pages"`
+}
+
+// ListTrades retrieves all trades for a user with optional filters
+func (db *DB) ListTrades(ctx context.Context, userID uuid.UUID, filters TradeFilters) ([]models.Trade, error) {
+	query := `
+		SELECT
+			t.id, t.user_id, t.symbol, t.trade_type, t.quantity,
+			t.entry_price, t.exit_price, t.stop_loss, t.fees, t.pnl, t.multiplier, t.broker_pnl, t.pnl_from_broker, t.currency, t.account_id,
+			t.opened_at, t.closed_at, t.created_at, t.updated_at, t.fingerprint, t.import_batch_id,
+			EXISTS(SELECT 1 FROM journal_entries je WHERE je.trade_id = t.id) as has_journal,
+			COALESCE(
+				(SELECT json_agg(tag.name)
+				FROM trade_tags tt
+				JOIN tags tag ON tt.tag_id = tag.id
+				WHERE tt.trade_id = t.id),
+				'[]'::json
+			) as tags
+		FROM trades t
+		WHERE t.user_id = $1`
+
+	filterClause, args := tradeFilterClause(filters, []interface{}{userID})
+	query += filterClause
+	argCount := len(args)
+
 	// Order by most recent first
 	query += " ORDER BY t.opened_at DESC"
 
@@ -132,8 +142,8 @@ func (db *DB) ListTrades(ctx context.Context, userID uuid.UUID, filters TradeFil
 
 		err := rows.Scan(
 			&trade.ID, &trade.UserID, &trade.Symbol, &trade.TradeType, &trade.Quantity,
-			&trade.EntryPrice, &trade.ExitPrice, &trade.Fees, &trade.PnL,
-			&trade.OpenedAt, &trade.ClosedAt, &trade.CreatedAt, &trade.UpdatedAt,
+			&trade.EntryPrice, &trade.ExitPrice, &trade.StopLoss, &trade.Fees, &trade.PnL, &trade.Multiplier, &trade.BrokerPnL, &trade.PnLFromBroker, &trade.Currency, &trade.AccountID,
+			&trade.OpenedAt, &trade.ClosedAt, &trade.CreatedAt, &trade.UpdatedAt, &trade.Fingerprint, &trade.ImportBatchID,
 			&trade.HasJournal, &tagsJSON,
 		)
 		if err != nil {
@@ -169,8 +179,8 @@ func (db *DB) GetTrade(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*mo
 	query := `
 		SELECT
 			t.id, t.user_id, t.symbol, t.trade_type, t.quantity,
-			t.entry_price, t.exit_price, t.fees, t.pnl,
-			t.opened_at, t.closed_at, t.created_at, t.updated_at,
+			t.entry_price, t.exit_price, t.stop_loss, t.fees, t.pnl, t.multiplier, t.broker_pnl, t.pnl_from_broker, t.currency, t.account_id,
+			t.opened_at, t.closed_at, t.created_at, t.updated_at, t.fingerprint, t.import_batch_id,
 			EXISTS(SELECT 1 FROM journal_entries je WHERE je.trade_id = t.id) as has_journal,
 			COALESCE(
 				(SELECT json_agg(tag.name)
@@ -187,8 +197,8 @@ func (db *DB) GetTrade(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*mo
 
 	err := db.QueryRow(query, id, userID).Scan(
 		&trade.ID, &trade.UserID, &trade.Symbol, &trade.TradeType, &trade.Quantity,
-		&trade.EntryPrice, &trade.ExitPrice, &trade.Fees, &trade.PnL,
-		&trade.OpenedAt, &trade.ClosedAt, &trade.CreatedAt, &trade.UpdatedAt,
+		&trade.EntryPrice, &trade.ExitPrice, &trade.StopLoss, &trade.Fees, &trade.PnL, &trade.Multiplier, &trade.BrokerPnL, &trade.PnLFromBroker, &trade.Currency, &trade.AccountID,
+		&trade.OpenedAt, &trade.ClosedAt, &trade.CreatedAt, &trade.UpdatedAt, &trade.Fingerprint, &trade.ImportBatchID,
 		&trade.HasJournal, &tagsJSON,
 	)
 
@@ -212,28 +222,62 @@ func (db *DB) GetTrade(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*mo
 		}
 	}
 
+	// Load executions
+	executions, err := db.ListExecutionsByTradeID(ctx, trade.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	trade.Executions = executions
+
+	// Load canceled and rejected orders
+	orders, err := db.ListOrderEventsByTradeID(ctx, trade.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	trade.Orders = orders
+
 	return &trade, nil
 }
 
-// CreateTrade inserts a new trade
+// CreateTrade inserts a new trade along with any executions it carries
 func (db *DB) CreateTrade(ctx context.Context, trade *models.Trade) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO trades (
 			user_id, symbol, trade_type, quantity, entry_price, exit_price,
-			fees, opened_at, closed_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			fees, opened_at, closed_at, multiplier, broker_pnl, currency, account_id, stop_loss
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, pnl, created_at, updated_at`
 
-	err := db.QueryRow(
+	err = tx.QueryRowContext(
+		ctx,
 		query,
 		trade.UserID, trade.Symbol, trade.TradeType, trade.Quantity,
 		trade.EntryPrice, trade.ExitPrice, trade.Fees, trade.OpenedAt, trade.ClosedAt,
+		trade.ContractMultiplier(), trade.BrokerPnL, trade.CurrencyCode(), trade.AccountID, trade.StopLoss,
 	).Scan(&trade.ID, &trade.PnL, &trade.CreatedAt, &trade.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to create trade: %w", err)
 	}
 
+	if err := insertExecutions(ctx, tx, trade.ID, trade.UserID, trade.Executions); err != nil {
+		return err
+	}
+
+	if err := insertOrderEvents(ctx, tx, trade.ID, trade.UserID, trade.Orders); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -242,7 +286,8 @@ func (db *DB) UpdateTrade(ctx context.Context, id uuid.UUID, userID uuid.UUID, t
 	query := `
 		UPDATE trades
 		SET symbol = $3, trade_type = $4, quantity = $5, entry_price = $6,
-		    exit_price = $7, fees = $8, opened_at = $9, closed_at = $10
+		    exit_price = $7, fees = $8, opened_at = $9, closed_at = $10,
+		    multiplier = $11, broker_pnl = $12, currency = $13, account_id = $14, stop_loss = $15
 		WHERE id = $1 AND user_id = $2
 		RETURNING pnl, updated_at`
 
@@ -250,6 +295,7 @@ func (db *DB) UpdateTrade(ctx context.Context, id uuid.UUID, userID uuid.UUID, t
 		query,
 		id, userID, trade.Symbol, trade.TradeType, trade.Quantity,
 		trade.EntryPrice, trade.ExitPrice, trade.Fees, trade.OpenedAt, trade.ClosedAt,
+		trade.ContractMultiplier(), trade.BrokerPnL, trade.CurrencyCode(), trade.AccountID, trade.StopLoss,
 	).Scan(&trade.PnL, &trade.UpdatedAt)
 
 	if err == sql.ErrNoRows {
@@ -285,45 +331,6 @@ func (db *DB) DeleteTrade(ctx context.Context, id uuid.UUID, userID uuid.UUID) e
 	return nil
 }
 
-// BulkCreateTrades inserts multiple trades (for CSV import)
-func (db *DB) BulkCreateTrades(ctx context.Context, trades []models.Trade) ([]uuid.UUID, error) {
-	tx, err := db.Begin()
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	stmt := `
-		INSERT INTO trades (
-			user_id, symbol, trade_type, quantity, entry_price, exit_price,
-			fees, opened_at, closed_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING id`
-
-	ids := make([]uuid.UUID, 0, len(trades))
-
-	for _, trade := range trades {
-		var id uuid.UUID
-		err := tx.QueryRow(
-			stmt,
-			trade.UserID, trade.Symbol, trade.TradeType, trade.Quantity,
-			trade.EntryPrice, trade.ExitPrice, trade.Fees, trade.OpenedAt, trade.ClosedAt,
-		).Scan(&id)
-
-		if err != nil {
-			return nil, fmt.Errorf("failed to insert trade: %w", err)
-		}
-
-		ids = append(ids, id)
-	}
-
-	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return ids, nil
-}
-
 // AddTagToTrade associates a tag with a trade
 func (db *DB) AddTagToTrade(ctx context.Context, tradeID uuid.UUID, tagID uuid.UUID, userID uuid.UUID) error {
 	// Verify trade ownership
@@ -398,59 +405,9 @@ func (db *DB) ListTradesPaginated(ctx context.Context, userID uuid.UUID, filters
 		filters.Offset = 0
 	}
 
-	// Build WHERE clause for count query
-	whereClause := "WHERE t.user_id = $1"
-	args := []interface{}{userID}
-	argCount := 1
-
 	// Apply same filters for counting
-	if filters.Symbol != "" {
-		argCount++
-		whereClause += fmt.Sprintf(" AND UPPER(t.symbol) = UPPER($%d)", argCount)
-		args = append(args, filters.Symbol)
-	}
-
-	if filters.TradeType != "" {
-		argCount++
-		whereClause += fmt.Sprintf(" AND t.trade_type = $%d", argCount)
-		args = append(args, filters.TradeType)
-	}
-
-	if filters.Status == "open" {
-		whereClause += " AND t.exit_price IS NULL"
-	} else if filters.Status == "closed" {
-		whereClause += " AND t.exit_price IS NOT NULL"
-	}
-
-	if filters.StartDate != "" {
-		argCount++
-		whereClause += fmt.Sprintf(" AND t.opened_at >= $%d", argCount)
-		args = append(args, filters.StartDate)
-	}
-
-	if filters.EndDate != "" {
-		argCount++
-		whereClause += fmt.Sprintf(" AND t.opened_at <= $%d", argCount)
-		args = append(args, filters.EndDate)
-	}
-
-	if filters.Strategy != "" {
-		argCount++
-		whereClause += fmt.Sprintf(" AND t.strategy = $%d", argCount)
-		args = append(args, filters.Strategy)
-	}
-
-	if filters.MinPnL != nil {
-		argCount++
-		whereClause += fmt.Sprintf(" AND t.pnl >= $%d", argCount)
-		args = append(args, *filters.MinPnL)
-	}
-
-	if filters.MaxPnL != nil {
-		argCount++
-		whereClause += fmt.Sprintf(" AND t.pnl <= $%d", argCount)
-		args = append(args, *filters.MaxPnL)
-	}
+	filterClause, args := tradeFilterClause(filters, []interface{}{userID})
+	whereClause := "WHERE t.user_id = $1" + filterClause
 
 	// Get total count
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM trades t %s", whereClause)
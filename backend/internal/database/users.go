@@ -90,6 +90,26 @@ func (db *DB) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, erro
 	return &user, nil
 }
 
+// GetUserTimezone returns the IANA time zone saved in a user's preferences, or
+// an empty string when none is set
+func (db *DB) GetUserTimezone(ctx context.Context, id uuid.UUID) (string, error) {
+	var timezone string
+	err := db.QueryRowContext(ctx, `
+		SELECT COALESCE(preferences->>'timezone', '')
+		FROM users
+		WHERE id = $1
+	`, id).Scan(&timezone)
+
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get user timezone: %w", err)
+	}
+
+	return timezone, nil
+}
+
 // CreateUser creates a new user
 func (db *DB) CreateUser(ctx context.Context, user *models.User) error {
 	_, err := db.ExecContext(ctx, `
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/database"
+	"github.com/tradepulse/api/internal/middleware"
+	"github.com/tradepulse/api/internal/models"
+)
+
+type AccountsHandler struct {
+	db *database.DB
+}
+
+func NewAccountsHandler(db *database.DB) *AccountsHandler {
+	return &AccountsHandler{db: db}
+}
+
+// accountRequest is the body of account create and update requests. The
+// active flag is a pointer so that leaving it out keeps an account active.
+type accountRequest struct {
+	Name            string             `json:"name"`
+	Broker          string             `json:"broker"`
+	AccountNumber   string             `json:"account_number"`
+	Currency        string             `json:"currency"`
+	AccountType     models.AccountType `json:"account_type"`
+	StartingBalance float64            `json:"starting_balance"`
+	IsActive        *bool              `json:"is_active"`
+}
+
+// ListAccounts handles GET /api/accounts
+func (h *AccountsHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+
+	accounts, err := h.db.ListAccounts(r.Context(), userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch accounts", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    accounts,
+	})
+}
+
+// GetAccount handles GET /api/accounts/{id}
+func (h *AccountsHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	account, err := h.db.GetAccount(r.Context(), accountID, userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch account", err)
+		return
+	}
+	if account == nil {
+		sendError(w, http.StatusNotFound, "Account not found", nil)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    account,
+	})
+}
+
+// CreateAccount handles POST /api/accounts
+func (h *AccountsHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+
+	var req accountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	account, msg := req.toAccount()
+	if msg != "" {
+		sendError(w, http.StatusBadRequest, msg, nil)
+		return
+	}
+	account.UserID = userID
+
+	if err := h.db.CreateAccount(r.Context(), &account); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to create account", err)
+		return
+	}
+
+	sendJSON(w, http.StatusCreated, map[string]interface{}{
+		"success": true,
+		"data":    account,
+	})
+}
+
+// UpdateAccount handles PUT /api/accounts/{id}
+func (h *AccountsHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	var req accountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	account, msg := req.toAccount()
+	if msg != "" {
+		sendError(w, http.StatusBadRequest, msg, nil)
+		return
+	}
+
+	if err := h.db.UpdateAccount(r.Context(), accountID, userID, &account); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to update account", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    account,
+	})
+}
+
+// DeleteAccount handles DELETE /api/accounts/{id}
+func (h *AccountsHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	if err := h.db.DeleteAccount(r.Context(), accountID, userID); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to delete account", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Account deleted successfully",
+	})
+}
+
+// GetAccountBalances handles GET /api/accounts/{id}/balances
+// Returns the account's daily balance: its starting balance plus the cash
+// ledger and the realized P&L of its closed trades, with each day's return.
+// Days run from "start_date" to "end_date" (YYYY-MM-DD, defaulting to the first
+// and last day with activity) and are calendar days in the user's time zone.
+// Ranges of more than database.MaxBalanceDays days are rejected.
+func (h *AccountsHandler) GetAccountBalances(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	query := r.URL.Query()
+	timezone, err := userTimezone(r, h.db, userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to get user timezone", err)
+		return
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid timezone", err)
+		return
+	}
+
+	startDate, endDate := query.Get("start_date"), query.Get("end_date")
+	for _, date := range []string{startDate, endDate} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			sendError(w, http.StatusBadRequest, "Dates must be YYYY-MM-DD", err)
+			return
+		}
+	}
+
+	account, err := h.db.GetAccount(r.Context(), accountID, userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch account", err)
+		return
+	}
+	if account == nil {
+		sendError(w, http.StatusNotFound, "Account not found", nil)
+		return
+	}
+
+	series, err := h.db.GetAccountBalances(r.Context(), account, loc, startDate, endDate)
+	if errors.Is(err, database.ErrBalanceRangeTooLong) {
+		sendError(w, http.StatusBadRequest, fmt.Sprintf("Balances cover at most %d days", database.MaxBalanceDays), err)
+		return
+	}
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to build account balances", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    series,
+	})
+}
+
+// toAccount validates the request and fills in defaults, returning a client
+// facing message when the request is invalid
+func (req accountRequest) toAccount() (models.Account, string) {
+	account := models.Account{
+		Name:            strings.TrimSpace(req.Name),
+		Broker:          strings.TrimSpace(req.Broker),
+		AccountNumber:   strings.TrimSpace(req.AccountNumber),
+		Currency:        strings.ToUpper(strings.TrimSpace(req.Currency)),
+		AccountType:     req.AccountType,
+		StartingBalance: req.StartingBalance,
+		IsActive:        true,
+	}
+	if req.IsActive != nil {
+		account.IsActive = *req.IsActive
+	}
+	if account.Currency == "" {
+		account.Currency = models.DefaultCurrency
+	}
+	if account.AccountType == "" {
+		account.AccountType = models.AccountCash
+	}
+
+	if account.Name == "" {
+		return account, "Account name is required"
+	}
+	if len(account.Currency) != 3 {
+		return account, "Currency must be a 3-letter ISO code"
+	}
+	if !account.AccountType.Valid() {
+		return account, "Invalid account type"
+	}
+
+	return account, ""
+}
+
+// checkAccount reports whether an account a trade or import refers to exists
+// and belongs to the user
+func checkAccount(r *http.Request, db *database.DB, accountID *uuid.UUID, userID uuid.UUID) (bool, error) {
+	if accountID == nil {
+		return true, nil
+	}
+	account, err := db.GetAccount(r.Context(), *accountID, userID)
+	if err != nil {
+		return false, err
+	}
+	return account != nil, nil
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/database"
+	"github.com/tradepulse/api/internal/middleware"
+	"github.com/tradepulse/api/internal/models"
+)
+
+type CashTransactionsHandler struct {
+	db *database.DB
+}
+
+func NewCashTransactionsHandler(db *database.DB) *CashTransactionsHandler {
+	return &CashTransactionsHandler{db: db}
+}
+
+// ListCashTransactions handles GET /api/cash-transactions
+// Optional filters: account_id, type, start_date and end_date
+func (h *CashTransactionsHandler) ListCashTransactions(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+
+	filters := database.CashTransactionFilters{
+		Type:      strings.ToUpper(r.URL.Query().Get("type")),
+		StartDate: r.URL.Query().Get("start_date"),
+		EndDate:   r.URL.Query().Get("end_date"),
+	}
+
+	if accountID := r.URL.Query().Get("account_id"); accountID != "" {
+		if _, err := uuid.Parse(accountID); err != nil {
+			sendError(w, http.StatusBadRequest, "Invalid account ID", err)
+			return
+		}
+		filters.AccountID = accountID
+	}
+
+	transactions, err := h.db.ListCashTransactions(r.Context(), userID, filters)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch cash transactions", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    transactions,
+	})
+}
+
+// GetCashTransaction handles GET /api/cash-transactions/{id}
+func (h *CashTransactionsHandler) GetCashTransaction(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid cash transaction ID", err)
+		return
+	}
+
+	transaction, err := h.db.GetCashTransaction(r.Context(), transactionID, userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch cash transaction", err)
+		return
+	}
+	if transaction == nil {
+		sendError(w, http.StatusNotFound, "Cash transaction not found", nil)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    transaction,
+	})
+}
+
+// CreateCashTransaction handles POST /api/cash-transactions
+func (h *CashTransactionsHandler) CreateCashTransaction(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+
+	var transaction models.CashTransaction
+	if err := json.NewDecoder(r.Body).Decode(&transaction); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if !h.validateCashTransaction(w, r, userID, &transaction) {
+		return
+	}
+	transaction.UserID = userID
+
+	if err := h.db.CreateCashTransaction(r.Context(), &transaction); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to create cash transaction", err)
+		return
+	}
+
+	sendJSON(w, http.StatusCreated, map[string]interface{}{
+		"success": true,
+		"data":    transaction,
+	})
+}
+
+// UpdateCashTransaction handles PUT /api/cash-transactions/{id}
+func (h *CashTransactionsHandler) UpdateCashTransaction(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid cash transaction ID", err)
+		return
+	}
+
+	var transaction models.CashTransaction
+	if err := json.NewDecoder(r.Body).Decode(&transaction); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if !h.validateCashTransaction(w, r, userID, &transaction) {
+		return
+	}
+
+	if err := h.db.UpdateCashTransaction(r.Context(), transactionID, userID, &transaction); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to update cash transaction", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    transaction,
+	})
+}
+
+// DeleteCashTransaction handles DELETE /api/cash-transactions/{id}
+func (h *CashTransactionsHandler) DeleteCashTransaction(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid cash transaction ID", err)
+		return
+	}
+
+	if err := h.db.DeleteCashTransaction(r.Context(), transactionID, userID); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to delete cash transaction", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Cash transaction deleted successfully",
+	})
+}
+
+// validateCashTransaction checks a transaction from a request and writes the
+// error response when it is invalid. Deposits always add to the account and
+// withdrawals, payouts and fees always take from it, whatever the sign sent;
+// the other types keep theirs.
+func (h *CashTransactionsHandler) validateCashTransaction(w http.ResponseWriter, r *http.Request, userID uuid.UUID, transaction *models.CashTransaction) bool {
+	transaction.Type = models.CashTransactionType(strings.ToUpper(string(transaction.Type)))
+	if !transaction.Type.Valid() {
+		sendError(w, http.StatusBadRequest, "Invalid cash transaction type", nil)
+		return false
+	}
+	if transaction.Amount == 0 {
+		sendError(w, http.StatusBadRequest, "Amount is required", nil)
+		return false
+	}
+	if transaction.OccurredAt.IsZero() {
+		sendError(w, http.StatusBadRequest, "occurred_at is required", nil)
+		return false
+	}
+
+	switch transaction.Type {
+	case models.CashDeposit:
+		transaction.Amount = math.Abs(transaction.Amount)
+	case models.CashWithdrawal, models.CashPayout, models.CashFee:
+		transaction.Amount = -math.Abs(transaction.Amount)
+	}
+
+	accountID := transaction.AccountID
+	if ok, err := checkAccount(r, h.db, &accountID, userID); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch account", err)
+		return false
+	} else if !ok {
+		sendError(w, http.StatusBadRequest, "Account not found", nil)
+		return false
+	}
+
+	return true
+}
@@ -2,14 +2,29 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/tradepulse/api/internal/database"
+	"github.com/tradepulse/api/internal/importers"
 	"github.com/tradepulse/api/internal/middleware"
 	"github.com/tradepulse/api/internal/models"
 	"github.com/tradepulse/api/internal/notifications"
 )
 
+const (
+	// maxUploadSize caps broker files held in memory during parsing
+	maxUploadSize = 10 << 20
+
+	// defaultImportTimezone is used for broker files whose times carry no zone
+	defaultImportTimezone = "America/New_York"
+)
+
 type CSVImportHandler struct {
 	db  *database.DB
 	bus *notifications.Bus
@@ -19,22 +34,296 @@ func NewCSVImportHandler(db *database.DB, bus *notifications.Bus) *CSVImportHand
 	return &CSVImportHandler{db: db, bus: bus}
 }
 
+// parsedImport is an import request turned into trades, ready to preview or commit
+type parsedImport struct {
+	source      models.ImportSource
+	fileName    string
+	onDuplicate database.DuplicateMode
+	trades      []models.Trade
+	cash        []models.CashTransaction
+	currency    string                 // account currency named by the file
+	accountID   *uuid.UUID             // account every trade is booked to, if chosen by the user
+	details     map[string]interface{} // source-specific response fields
+}
+
+// importError is a failure to read an import request, with the status to report
+type importError struct {
+	status  int
+	message string
+	err     error
+}
+
+func badImport(message string, err error) *importError {
+	return &importError{status: http.StatusBadRequest, message: message, err: err}
+}
+
+type importParser func(r *http.Request, userID uuid.UUID) (*parsedImport, *importError)
+
 // ImportCSV handles POST /api/trades/import-csv
+// Accepts trades already parsed by the client
 func (h *CSVImportHandler) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	h.commitImport(w, r, h.parseTradesBody)
+}
+
+// ImportDAS handles POST /api/trades/import/das
+// Accepts a multipart upload of a DAS Trader order-event log ("file"). The log has
+// no date column, so the trade date comes from the "trade_date" field (YYYY-MM-DD)
+// or, when omitted, from the file name. Times are read in "timezone"
+// (default America/New_York).
+func (h *CSVImportHandler) ImportDAS(w http.ResponseWriter, r *http.Request) {
+	h.commitImport(w, r, h.parseDASUpload)
+}
+
+// ImportIBKR handles POST /api/trades/import/ibkr
+// Accepts a multipart upload of an Interactive Brokers Flex Query XML report
+// ("file"). Times are read in "timezone" (default America/New_York), which should
+// match the time zone the Flex query reports in.
+func (h *CSVImportHandler) ImportIBKR(w http.ResponseWriter, r *http.Request) {
+	h.commitImport(w, r, h.parseIBKRUpload)
+}
+
+// ImportTOS handles POST /api/trades/import/thinkorswim
+// Accepts a multipart upload of a thinkorswim "Account Statement" CSV ("file").
+// Times are read in "timezone" (default America/New_York). Deposits, dividends
+// and other cash activity in the statement go into the account's cash ledger.
+func (h *CSVImportHandler) ImportTOS(w http.ResponseWriter, r *http.Request) {
+	h.commitImport(w, r, h.parseTOSUpload)
+}
+
+// ImportNinjaTrader handles POST /api/trades/import/ninjatrader
+// Accepts a multipart upload of a NinjaTrader Executions grid CSV export ("file").
+// Futures instruments get their contract point value as the trade multiplier.
+// Times are read in "timezone" (default America/New_York).
+func (h *CSVImportHandler) ImportNinjaTrader(w http.ResponseWriter, r *http.Request) {
+	h.commitImport(w, r, h.parseNinjaTraderUpload)
+}
+
+// ImportTradovate handles POST /api/trades/import/tradovate
+// Accepts a multipart upload of a Tradovate Fills or Performance CSV export
+// ("file"). Futures contracts get their point value as the trade multiplier.
+// Times are read in "timezone" (default America/New_York).
+func (h *CSVImportHandler) ImportTradovate(w http.ResponseWriter, r *http.Request) {
+	h.commitImport(w, r, h.parseTradovateUpload)
+}
+
+// ImportMetaTrader handles POST /api/trades/import/metatrader
+// Accepts a multipart upload of a MetaTrader 4 detailed statement or MetaTrader 5
+// trade history report, as HTML or MT5 Excel ("file"). Volumes are kept in lots
+// with the contract size as the trade multiplier: 100,000 for currency pairs,
+// the usual size for metals, and otherwise 1 unless given in "contract_sizes"
+// ("US30=1,GER40=25"). Trades take the account currency, and balance, fee and
+// interest rows go into the account's cash ledger. Times are server
+// times, read in "timezone" (default America/New_York).
+func (h *CSVImportHandler) ImportMetaTrader(w http.ResponseWriter, r *http.Request) {
+	h.commitImport(w, r, h.parseMetaTraderUpload)
+}
+
+// ImportOFX handles POST /api/trades/import/ofx
+// Accepts a multipart upload of an OFX or QFX investment statement download
+// ("file"), in OFX 1.x SGML or 2.x XML. Income, deposits, interest and fees in the
+// statement go into the account's cash ledger. Trades take the statement
+// currency. Times without a zone are read in "timezone" (default America/New_York).
+func (h *CSVImportHandler) ImportOFX(w http.ResponseWriter, r *http.Request) {
+	h.commitImport(w, r, h.parseOFXUpload)
+}
+
+// UploadCSV handles POST /api/trades/import/upload
+// Accepts a multipart upload of a raw broker CSV ("file") and the ID of a saved
+// import profile ("profile_id") describing its columns. "trade_date" (YYYY-MM-DD)
+// supplies the date for files without a date column; otherwise the date in the
+// file name is used.
+func (h *CSVImportHandler) UploadCSV(w http.ResponseWriter, r *http.Request) {
+	h.commitImport(w, r, h.parseProfileUpload)
+}
+
+// PreviewImport handles POST /api/imports/preview
+// Parses an import exactly as CommitImport would and reports the trades, any
+// validation errors and how each trade would be treated, without writing anything.
+func (h *CSVImportHandler) PreviewImport(w http.ResponseWriter, r *http.Request) {
 	userID, _ := middleware.GetUserID(r)
 
+	parsed, ierr := h.parseImport(r, userID)
+	if ierr == nil {
+		ierr = h.resolveImportAccount(r, userID, parsed)
+	}
+	if ierr != nil {
+		sendError(w, ierr.status, ierr.message, ierr.err)
+		return
+	}
+
+	validationErrors := importers.ValidateTrades(parsed.trades)
+
+	data := map[string]interface{}{
+		"source":            parsed.source,
+		"file_name":         parsed.fileName,
+		"trades":            parsed.trades,
+		"validation_errors": validationErrors,
+	}
+
+	// Invalid trades may not even fit the schema, so only valid imports are dry-run
+	if len(validationErrors) == 0 {
+		result, err := h.db.BulkCreateTrades(r.Context(), parsed.trades, database.ImportOptions{
+			OnDuplicate: parsed.onDuplicate,
+			Source:      parsed.source,
+			FileName:    parsed.fileName,
+			AccountID:   parsed.accountID,
+			Cash:        parsed.cash,
+			Currency:    parsed.currency,
+			DryRun:      true,
+		})
+		if err != nil {
+			sendError(w, http.StatusInternalServerError, "Failed to preview import", err)
+			return
+		}
+		data = importResponse(result, data)
+		delete(data, "trade_ids")
+	}
+
+	for k, v := range parsed.details {
+		data[k] = v
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    data,
+	})
+}
+
+// CommitImport handles POST /api/imports/commit
+// Accepts the same request as PreviewImport and imports it as a new batch
+func (h *CSVImportHandler) CommitImport(w http.ResponseWriter, r *http.Request) {
+	h.commitImport(w, r, h.parseImport)
+}
+
+func (h *CSVImportHandler) commitImport(w http.ResponseWriter, r *http.Request, parse importParser) {
+	userID, _ := middleware.GetUserID(r)
+
+	parsed, ierr := parse(r, userID)
+	if ierr == nil {
+		ierr = h.resolveImportAccount(r, userID, parsed)
+	}
+	if ierr != nil {
+		sendError(w, ierr.status, ierr.message, ierr.err)
+		return
+	}
+
+	if validationErrors := importers.ValidateTrades(parsed.trades); len(validationErrors) > 0 {
+		sendJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"success": false,
+			"error": map[string]string{
+				"message": "Import has validation errors",
+			},
+			"data": map[string]interface{}{
+				"validation_errors": validationErrors,
+			},
+		})
+		return
+	}
+
+	// Bulk insert trades, skipping ones imported before
+	result, err := h.db.BulkCreateTrades(r.Context(), parsed.trades, database.ImportOptions{
+		OnDuplicate: parsed.onDuplicate,
+		Source:      parsed.source,
+		FileName:    parsed.fileName,
+		AccountID:   parsed.accountID,
+		Cash:        parsed.cash,
+		Currency:    parsed.currency,
+	})
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to import trades", err)
+		return
+	}
+
+	// Send notification
+	h.publishImport(userID, importTitle(parsed.source), result)
+
+	sendJSON(w, http.StatusCreated, map[string]interface{}{
+		"success": true,
+		"data":    importResponse(result, parsed.details),
+	})
+}
+
+// parseImport reads a preview or commit request: a multipart upload whose
+// "source" field names one of the file importers, or a JSON body of
+// client-parsed trades
+func (h *CSVImportHandler) parseImport(r *http.Request, userID uuid.UUID) (*parsedImport, *importError) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return h.parseTradesBody(r, userID)
+	}
+
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		return nil, badImport("Invalid multipart upload", err)
+	}
+
+	switch models.ImportSource(strings.ToUpper(r.FormValue("source"))) {
+	case models.ImportSourceDAS:
+		return h.parseDASUpload(r, userID)
+	case models.ImportSourceIBKR:
+		return h.parseIBKRUpload(r, userID)
+	case models.ImportSourceTOS:
+		return h.parseTOSUpload(r, userID)
+	case models.ImportSourceNinjaTrader:
+		return h.parseNinjaTraderUpload(r, userID)
+	case models.ImportSourceTradovate:
+		return h.parseTradovateUpload(r, userID)
+	case models.ImportSourceMetaTrader:
+		return h.parseMetaTraderUpload(r, userID)
+	case models.ImportSourceOFX:
+		return h.parseOFXUpload(r, userID)
+	case models.ImportSourceProfile:
+		return h.parseProfileUpload(r, userID)
+	}
+	return nil, badImport(fmt.Sprintf("source must be one of %s, %s, %s, %s, %s, %s, %s or %s",
+		models.ImportSourceDAS, models.ImportSourceIBKR, models.ImportSourceTOS, models.ImportSourceNinjaTrader,
+		models.ImportSourceTradovate, models.ImportSourceMetaTrader, models.ImportSourceOFX, models.ImportSourceProfile), nil)
+}
+
+// resolveImportAccount reads the optional "account_id" form field naming the
+// account an upload is booked to, and checks that the chosen account belongs
+// to the user. Without one, trades go to the accounts matching the account
+// numbers in the file.
+func (h *CSVImportHandler) resolveImportAccount(r *http.Request, userID uuid.UUID, parsed *parsedImport) *importError {
+	if parsed.accountID == nil {
+		if value := r.FormValue("account_id"); value != "" {
+			accountID, err := uuid.Parse(value)
+			if err != nil {
+				return badImport("Invalid account_id", err)
+			}
+			parsed.accountID = &accountID
+		}
+	}
+
+	ok, err := checkAccount(r, h.db, parsed.accountID, userID)
+	if err != nil {
+		return &importError{status: http.StatusInternalServerError, message: "Failed to fetch account", err: err}
+	}
+	if !ok {
+		return badImport("Account not found", nil)
+	}
+	return nil
+}
+
+// parseTradesBody reads trades parsed by the client from a JSON body
+func (h *CSVImportHandler) parseTradesBody(r *http.Request, userID uuid.UUID) (*parsedImport, *importError) {
 	var req struct {
-		Trades []models.Trade `json:"trades"`
+		Trades      []models.Trade `json:"trades"`
+		FileName    string         `json:"file_name"`
+		OnDuplicate string         `json:"on_duplicate"`
+		AccountID   *uuid.UUID     `json:"account_id"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		sendError(w, http.StatusBadRequest, "Invalid request body", err)
-		return
+		return nil, badImport("Invalid request body", err)
 	}
 
 	if len(req.Trades) == 0 {
-		sendError(w, http.StatusBadRequest, "No trades to import", nil)
-		return
+		return nil, badImport("No trades to import", nil)
+	}
+
+	onDuplicate, err := database.ParseDuplicateMode(req.OnDuplicate)
+	if err != nil {
+		return nil, badImport(err.Error(), err)
 	}
 
 	// Set user ID for all trades
@@ -42,30 +331,451 @@ func (h *CSVImportHandler) ImportCSV(w http.ResponseWriter, r *http.Request) {
 		req.Trades[i].UserID = userID
 	}
 
-	// Bulk insert trades
-	tradeIDs, err := h.db.BulkCreateTrades(r.Context(), req.Trades)
+	return &parsedImport{
+		source:      models.ImportSourceCSV,
+		fileName:    req.FileName,
+		onDuplicate: onDuplicate,
+		trades:      req.Trades,
+		accountID:   req.AccountID,
+	}, nil
+}
+
+// parseDASUpload reads a multipart DAS order-event log upload
+func (h *CSVImportHandler) parseDASUpload(r *http.Request, userID uuid.UUID) (*parsedImport, *importError) {
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		return nil, badImport("Invalid multipart upload", err)
+	}
+
+	file, fileHeader, err := r.FormFile("file")
 	if err != nil {
-		sendError(w, http.StatusInternalServerError, "Failed to import trades", err)
-		return
+		return nil, badImport("File is required", err)
+	}
+	defer file.Close()
+
+	onDuplicate, err := database.ParseDuplicateMode(r.FormValue("on_duplicate"))
+	if err != nil {
+		return nil, badImport(err.Error(), err)
+	}
+
+	loc, err := time.LoadLocation(formValueOr(r, "timezone", defaultImportTimezone))
+	if err != nil {
+		return nil, badImport("Invalid timezone", err)
+	}
+
+	tradeDate, err := uploadTradeDate(r, fileHeader.Filename, loc)
+	if err != nil {
+		return nil, badImport(err.Error(), err)
+	}
+	if tradeDate.IsZero() {
+		return nil, badImport("trade_date is required when the file name has no date", nil)
+	}
+
+	orderLog, err := importers.ParseDASOrderLog(file, tradeDate)
+	if err != nil {
+		return nil, badImport("Failed to parse DAS order log: "+err.Error(), err)
+	}
+
+	if len(orderLog.Executions) == 0 {
+		return nil, badImport("No executions found in file", nil)
+	}
+
+	open, importErr := h.openTrades(r, userID, models.ImportSourceDAS)
+	if importErr != nil {
+		return nil, importErr
+	}
+
+	trades := importers.BuildTrades(orderLog.Executions, open)
+	unlinked := importers.LinkOrders(trades, orderLog.Orders)
+
+	for i := range trades {
+		trades[i].UserID = userID
+	}
+
+	return &parsedImport{
+		source:      models.ImportSourceDAS,
+		fileName:    fileHeader.Filename,
+		onDuplicate: onDuplicate,
+		trades:      trades,
+		details: map[string]interface{}{
+			"trade_date":      tradeDate.Format("2006-01-02"),
+			"execution_count": len(orderLog.Executions),
+			"order_count":     len(orderLog.Orders),
+			"unlinked_orders": unlinked,
+		},
+	}, nil
+}
+
+// brokerFile is what a broker file parser reads out of an upload
+type brokerFile struct {
+	executions []models.Execution
+	cash       []importers.CashActivity
+	currency   string                 // account currency, when the file names one
+	details    map[string]interface{} // source-specific response fields
+}
+
+// brokerFileParser reads a broker file. Times without a zone are read in loc;
+// open holds the positions earlier imports from the same source left open.
+type brokerFileParser func(file io.Reader, loc *time.Location, open []models.Trade) (*brokerFile, error)
+
+// parseBrokerFile reads a multipart upload of a broker file ("file") that needs
+// no options beyond "timezone" (default America/New_York) and "on_duplicate",
+// and builds its executions into trades. Cash activity in the file is imported
+// into the cash ledger along with them.
+func (h *CSVImportHandler) parseBrokerFile(r *http.Request, userID uuid.UUID, source models.ImportSource, label string, parse brokerFileParser) (*parsedImport, *importError) {
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		return nil, badImport("Invalid multipart upload", err)
+	}
+
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		return nil, badImport("File is required", err)
+	}
+	defer file.Close()
+
+	onDuplicate, err := database.ParseDuplicateMode(r.FormValue("on_duplicate"))
+	if err != nil {
+		return nil, badImport(err.Error(), err)
+	}
+
+	loc, err := time.LoadLocation(formValueOr(r, "timezone", defaultImportTimezone))
+	if err != nil {
+		return nil, badImport("Invalid timezone", err)
+	}
+
+	open, importErr := h.openTrades(r, userID, source)
+	if importErr != nil {
+		return nil, importErr
+	}
+
+	parsedFile, err := parse(file, loc, open)
+	if err != nil {
+		return nil, badImport("Failed to parse "+label+": "+err.Error(), err)
+	}
+
+	if len(parsedFile.executions) == 0 && len(parsedFile.cash) == 0 {
+		return nil, badImport("No executions found in file", nil)
+	}
+
+	trades := importers.BuildTrades(parsedFile.executions, open)
+	for i := range trades {
+		trades[i].UserID = userID
+		trades[i].Currency = parsedFile.currency
+	}
+
+	details := parsedFile.details
+	if details == nil {
+		details = make(map[string]interface{})
+	}
+	details["execution_count"] = len(parsedFile.executions)
+	details["pnl_differences"] = importers.ReconcilePnL(trades)
+	if parsedFile.cash != nil {
+		details["cash_activity"] = parsedFile.cash
+	}
+
+	return &parsedImport{
+		source:      source,
+		fileName:    fileHeader.Filename,
+		onDuplicate: onDuplicate,
+		trades:      trades,
+		cash:        cashTransactions(userID, parsedFile.cash),
+		currency:    parsedFile.currency,
+		details:     details,
+	}, nil
+}
+
+// openTrades returns the positions earlier imports from the source left open,
+// for the import to continue
+func (h *CSVImportHandler) openTrades(r *http.Request, userID uuid.UUID, source models.ImportSource) ([]models.Trade, *importError) {
+	open, err := h.db.ListOpenImportedTrades(r.Context(), userID, source)
+	if err != nil {
+		return nil, &importError{status: http.StatusInternalServerError, message: "Failed to fetch open positions", err: err}
+	}
+	return open, nil
+}
+
+// cashTransactions turns the cash activity of a statement into ledger entries
+func cashTransactions(userID uuid.UUID, activity []importers.CashActivity) []models.CashTransaction {
+	transactions := make([]models.CashTransaction, 0, len(activity))
+	for _, a := range activity {
+		transactions = append(transactions, models.CashTransaction{
+			UserID:      userID,
+			Type:        models.CashTransactionType(a.Type),
+			Amount:      a.Amount,
+			Description: a.Description,
+			Reference:   a.Reference,
+			OccurredAt:  a.OccurredAt,
+			Account:     a.Account,
+		})
+	}
+	return transactions
+}
+
+// parseIBKRUpload reads a multipart Interactive Brokers Flex Query upload
+func (h *CSVImportHandler) parseIBKRUpload(r *http.Request, userID uuid.UUID) (*parsedImport, *importError) {
+	return h.parseBrokerFile(r, userID, models.ImportSourceIBKR, "IBKR Flex report",
+		func(file io.Reader, loc *time.Location, open []models.Trade) (*brokerFile, error) {
+			statement, err := importers.ParseIBKRFlex(file, loc)
+			if err != nil {
+				return nil, err
+			}
+			return &brokerFile{
+				executions: statement.Executions,
+				details: map[string]interface{}{
+					"accounts":      statement.Accounts,
+					"from_date":     statement.FromDate,
+					"to_date":       statement.ToDate,
+					"skipped_count": statement.Skipped,
+				},
+			}, nil
+		})
+}
+
+// parseTOSUpload reads a multipart thinkorswim account statement upload
+func (h *CSVImportHandler) parseTOSUpload(r *http.Request, userID uuid.UUID) (*parsedImport, *importError) {
+	return h.parseBrokerFile(r, userID, models.ImportSourceTOS, "thinkorswim statement",
+		func(file io.Reader, loc *time.Location, open []models.Trade) (*brokerFile, error) {
+			statement, err := importers.ParseTOSStatement(file, loc, open)
+			if err != nil {
+				return nil, err
+			}
+			return &brokerFile{
+				executions: statement.Executions,
+				cash:       statement.Cash,
+				details: map[string]interface{}{
+					"account":          statement.Account,
+					"unmatched_closes": statement.Unmatched,
+				},
+			}, nil
+		})
+}
+
+// parseNinjaTraderUpload reads a multipart NinjaTrader executions export upload
+func (h *CSVImportHandler) parseNinjaTraderUpload(r *http.Request, userID uuid.UUID) (*parsedImport, *importError) {
+	return h.parseBrokerFile(r, userID, models.ImportSourceNinjaTrader, "NinjaTrader executions",
+		func(file io.Reader, loc *time.Location, open []models.Trade) (*brokerFile, error) {
+			executions, err := importers.ParseNinjaTraderExecutions(file, loc)
+			return &brokerFile{executions: executions}, err
+		})
+}
+
+// parseTradovateUpload reads a multipart Tradovate fills or performance export upload
+func (h *CSVImportHandler) parseTradovateUpload(r *http.Request, userID uuid.UUID) (*parsedImport, *importError) {
+	return h.parseBrokerFile(r, userID, models.ImportSourceTradovate, "Tradovate export",
+		func(file io.Reader, loc *time.Location, open []models.Trade) (*brokerFile, error) {
+			executions, err := importers.ParseTradovate(file, loc)
+			return &brokerFile{executions: executions}, err
+		})
+}
+
+// parseMetaTraderUpload reads a multipart MetaTrader statement upload
+func (h *CSVImportHandler) parseMetaTraderUpload(r *http.Request, userID uuid.UUID) (*parsedImport, *importError) {
+	return h.parseBrokerFile(r, userID, models.ImportSourceMetaTrader, "MetaTrader statement",
+		func(file io.Reader, loc *time.Location, open []models.Trade) (*brokerFile, error) {
+			contractSizes, err := importers.ParseContractSizes(r.FormValue("contract_sizes"))
+			if err != nil {
+				return nil, err
+			}
+			statement, err := importers.ParseMetaTraderStatement(file, loc, contractSizes)
+			if err != nil {
+				return nil, err
+			}
+			return &brokerFile{
+				executions: statement.Executions,
+				cash:       statement.Cash,
+				currency:   statement.Currency,
+				details: map[string]interface{}{
+					"platform":      statement.Platform,
+					"account":       statement.Account,
+					"currency":      statement.Currency,
+					"skipped_count": statement.Skipped,
+				},
+			}, nil
+		})
+}
+
+// parseOFXUpload reads a multipart OFX or QFX statement upload
+func (h *CSVImportHandler) parseOFXUpload(r *http.Request, userID uuid.UUID) (*parsedImport, *importError) {
+	return h.parseBrokerFile(r, userID, models.ImportSourceOFX, "OFX statement",
+		func(file io.Reader, loc *time.Location, open []models.Trade) (*brokerFile, error) {
+			statement, err := importers.ParseOFX(file, loc)
+			if err != nil {
+				return nil, err
+			}
+			return &brokerFile{
+				executions: statement.Executions,
+				cash:       statement.Cash,
+				currency:   statement.Currency,
+				details: map[string]interface{}{
+					"accounts":      statement.Accounts,
+					"currency":      statement.Currency,
+					"skipped_count": statement.Skipped,
+				},
+			}, nil
+		})
+}
+
+// parseProfileUpload reads a multipart broker CSV upload using a saved import profile
+func (h *CSVImportHandler) parseProfileUpload(r *http.Request, userID uuid.UUID) (*parsedImport, *importError) {
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		return nil, badImport("Invalid multipart upload", err)
+	}
+
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		return nil, badImport("File is required", err)
+	}
+	defer file.Close()
+
+	profileID, err := uuid.Parse(r.FormValue("profile_id"))
+	if err != nil {
+		return nil, badImport("Invalid import profile ID", err)
+	}
+
+	onDuplicate, err := database.ParseDuplicateMode(r.FormValue("on_duplicate"))
+	if err != nil {
+		return nil, badImport(err.Error(), err)
+	}
+
+	profile, err := h.db.GetImportProfile(r.Context(), profileID, userID)
+	if err != nil {
+		return nil, &importError{status: http.StatusInternalServerError, message: "Failed to fetch import profile", err: err}
+	}
+	if profile == nil {
+		return nil, &importError{status: http.StatusNotFound, message: "Import profile not found"}
+	}
+
+	loc, err := time.LoadLocation(profile.Timezone)
+	if err != nil {
+		return nil, badImport("Invalid timezone in import profile", err)
+	}
+
+	tradeDate, err := uploadTradeDate(r, fileHeader.Filename, loc)
+	if err != nil {
+		return nil, badImport(err.Error(), err)
+	}
+
+	executions, err := importers.ParseWithProfile(file, *profile, tradeDate)
+	if err != nil {
+		return nil, badImport("Failed to parse file: "+err.Error(), err)
+	}
+
+	if len(executions) == 0 {
+		return nil, badImport("No executions found in file", nil)
+	}
+
+	open, importErr := h.openTrades(r, userID, models.ImportSourceProfile)
+	if importErr != nil {
+		return nil, importErr
+	}
+
+	trades := importers.BuildTrades(executions, open)
+	for i := range trades {
+		trades[i].UserID = userID
+	}
+
+	return &parsedImport{
+		source:      models.ImportSourceProfile,
+		fileName:    fileHeader.Filename,
+		onDuplicate: onDuplicate,
+		trades:      trades,
+		details: map[string]interface{}{
+			"profile_id":      profile.ID,
+			"execution_count": len(executions),
+		},
+	}, nil
+}
+
+func importTitle(source models.ImportSource) string {
+	switch source {
+	case models.ImportSourceDAS:
+		return "DAS Import Complete"
+	case models.ImportSourceIBKR:
+		return "IBKR Import Complete"
+	case models.ImportSourcePropReports:
+		return "PropReports Import Complete"
+	case models.ImportSourceTOS:
+		return "thinkorswim Import Complete"
+	case models.ImportSourceNinjaTrader:
+		return "NinjaTrader Import Complete"
+	case models.ImportSourceTradovate:
+		return "Tradovate Import Complete"
+	case models.ImportSourceMetaTrader:
+		return "MetaTrader Import Complete"
+	case models.ImportSourceOFX:
+		return "OFX Import Complete"
+	}
+	return "CSV Import Complete"
+}
+
+// publishImport notifies the user of the outcome of an import
+func (h *CSVImportHandler) publishImport(userID uuid.UUID, title string, result *database.ImportResult) {
+	message := fmt.Sprintf("Successfully imported %d trades", result.Inserted)
+	if result.Duplicates > 0 || result.Conflicts > 0 {
+		message += fmt.Sprintf(" (%d duplicates skipped, %d conflicts)", result.Duplicates, result.Conflicts)
+	}
+	if result.CashInserted > 0 {
+		message += fmt.Sprintf(" and %d cash transactions", result.CashInserted)
 	}
 
-	// Send notification
 	h.bus.Publish(
 		notifications.NotificationTypeCSVImport,
 		userID,
-		"CSV Import Complete",
-		"Successfully imported "+string(rune(len(tradeIDs)))+" trades",
+		title,
+		message,
 		map[string]interface{}{
-			"count":     len(tradeIDs),
-			"trade_ids": tradeIDs,
+			"batch_id":        result.BatchID,
+			"count":           result.Inserted,
+			"updated_count":   result.Updated,
+			"duplicate_count": result.Duplicates,
+			"conflict_count":  result.Conflicts,
+			"trade_ids":       result.TradeIDs,
 		},
 	)
+}
 
-	sendJSON(w, http.StatusCreated, map[string]interface{}{
-		"success": true,
-		"data": map[string]interface{}{
-			"imported_count": len(tradeIDs),
-			"trade_ids":      tradeIDs,
-		},
-	})
+// importResponse builds the response data for an import, merged with any
+// source-specific fields
+func importResponse(result *database.ImportResult, extra map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{
+		"imported_count":  result.Inserted,
+		"updated_count":   result.Updated,
+		"duplicate_count": result.Duplicates,
+		"conflict_count":  result.Conflicts,
+		"conflicts":       result.Conflicted,
+		"trade_ids":       result.TradeIDs,
+	}
+	if result.BatchID != nil {
+		data["batch_id"] = result.BatchID
+	}
+	if result.CashInserted+result.CashDuplicates+result.CashUnassigned > 0 {
+		data["cash_imported_count"] = result.CashInserted
+		data["cash_duplicate_count"] = result.CashDuplicates
+		data["cash_unassigned_count"] = result.CashUnassigned
+	}
+	for k, v := range extra {
+		data[k] = v
+	}
+	return data
+}
+
+// uploadTradeDate reads the "trade_date" form field, falling back to a date in
+// the file name. It returns the zero time when neither is present.
+func uploadTradeDate(r *http.Request, filename string, loc *time.Location) (time.Time, error) {
+	if value := r.FormValue("trade_date"); value != "" {
+		tradeDate, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid trade_date, expected YYYY-MM-DD")
+		}
+		return tradeDate, nil
+	}
+
+	tradeDate, _ := importers.TradeDateFromFilename(filename, loc)
+	return tradeDate, nil
+}
+
+func formValueOr(r *http.Request, key, fallback string) string {
+	if value := r.FormValue(key); value != "" {
+		return value
+	}
+	return fallback
 }
@@ -314,24 +314,6 @@ func CreateTag(db *database.DB, logger *slog.Logger) http.HandlerFunc {
 	}
 }
 
-func GetSummaryMetrics(db *database.DB, logger *slog.Logger) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		writeError(w, http.StatusNotImplemented, "NOT_IMPLEMENTED", "Endpoint not yet implemented")
-	}
-}
-
-func GetMetricsBySymbol(db *database.DB, logger *slog.Logger) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		writeError(w, http.StatusNotImplemented, "NOT_IMPLEMENTED", "Endpoint not yet implemented")
-	}
-}
-
-func GetDailyPerformance(db *database.DB, logger *slog.Logger) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		writeError(w, http.StatusNotImplemented, "NOT_IMPLEMENTED", "Endpoint not yet implemented")
-	}
-}
-
 // RuleSet handlers
 func ListRuleSets(db *database.DB, logger *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/database"
+	"github.com/tradepulse/api/internal/middleware"
+	"github.com/tradepulse/api/internal/models"
+	"github.com/tradepulse/api/internal/notifications"
+)
+
+type ImportBatchesHandler struct {
+	db  *database.DB
+	bus *notifications.Bus
+}
+
+func NewImportBatchesHandler(db *database.DB, bus *notifications.Bus) *ImportBatchesHandler {
+	return &ImportBatchesHandler{db: db, bus: bus}
+}
+
+// ListImportBatches handles GET /api/imports
+func (h *ImportBatchesHandler) ListImportBatches(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+
+	batches, err := h.db.ListImportBatches(r.Context(), userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch import batches", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    batches,
+	})
+}
+
+// GetImportBatch handles GET /api/imports/{id}
+func (h *ImportBatchesHandler) GetImportBatch(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	batchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid import batch ID", err)
+		return
+	}
+
+	batch, err := h.db.GetImportBatch(r.Context(), batchID, userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch import batch", err)
+		return
+	}
+	if batch == nil {
+		sendError(w, http.StatusNotFound, "Import batch not found", nil)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    batch,
+	})
+}
+
+// RollbackImportBatch handles POST /api/imports/{id}/rollback
+// Deletes every trade the batch created, with its tags and journal entries
+func (h *ImportBatchesHandler) RollbackImportBatch(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	batchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid import batch ID", err)
+		return
+	}
+
+	batch, err := h.db.GetImportBatch(r.Context(), batchID, userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch import batch", err)
+		return
+	}
+	if batch == nil {
+		sendError(w, http.StatusNotFound, "Import batch not found", nil)
+		return
+	}
+	if batch.Status == models.ImportBatchRolledBack {
+		sendError(w, http.StatusConflict, "Import batch was already rolled back", nil)
+		return
+	}
+
+	rollback, err := h.db.RollbackImportBatch(r.Context(), batchID, userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to roll back import batch", err)
+		return
+	}
+
+	h.bus.Publish(
+		notifications.NotificationTypeImportRollback,
+		userID,
+		"Import Rolled Back",
+		fmt.Sprintf("Removed %d trades imported from %s", rollback.DeletedTrades, rollback.Batch.Source),
+		rollback,
+	)
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    rollback,
+	})
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/database"
+	"github.com/tradepulse/api/internal/importers"
+	"github.com/tradepulse/api/internal/middleware"
+	"github.com/tradepulse/api/internal/models"
+)
+
+type ImportProfilesHandler struct {
+	db *database.DB
+}
+
+func NewImportProfilesHandler(db *database.DB) *ImportProfilesHandler {
+	return &ImportProfilesHandler{db: db}
+}
+
+// ListImportProfiles handles GET /api/import-profiles
+func (h *ImportProfilesHandler) ListImportProfiles(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+
+	profiles, err := h.db.ListImportProfiles(r.Context(), userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch import profiles", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    profiles,
+	})
+}
+
+// GetImportProfile handles GET /api/import-profiles/{id}
+func (h *ImportProfilesHandler) GetImportProfile(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	profileID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid import profile ID", err)
+		return
+	}
+
+	profile, err := h.db.GetImportProfile(r.Context(), profileID, userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch import profile", err)
+		return
+	}
+	if profile == nil {
+		sendError(w, http.StatusNotFound, "Import profile not found", nil)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    profile,
+	})
+}
+
+// CreateImportProfile handles POST /api/import-profiles
+func (h *ImportProfilesHandler) CreateImportProfile(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+
+	var profile models.ImportProfile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	profile.UserID = userID
+	applyImportProfileDefaults(&profile)
+
+	if err := importers.ValidateProfile(profile); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid import profile: "+err.Error(), nil)
+		return
+	}
+
+	if err := h.db.CreateImportProfile(r.Context(), &profile); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to create import profile", err)
+		return
+	}
+
+	sendJSON(w, http.StatusCreated, map[string]interface{}{
+		"success": true,
+		"data":    profile,
+	})
+}
+
+// UpdateImportProfile handles PUT /api/import-profiles/{id}
+func (h *ImportProfilesHandler) UpdateImportProfile(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	profileID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid import profile ID", err)
+		return
+	}
+
+	var profile models.ImportProfile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	applyImportProfileDefaults(&profile)
+
+	if err := importers.ValidateProfile(profile); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid import profile: "+err.Error(), nil)
+		return
+	}
+
+	if err := h.db.UpdateImportProfile(r.Context(), profileID, userID, &profile); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to update import profile", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    profile,
+	})
+}
+
+// DeleteImportProfile handles DELETE /api/import-profiles/{id}
+func (h *ImportProfilesHandler) DeleteImportProfile(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r)
+	profileID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid import profile ID", err)
+		return
+	}
+
+	if err := h.db.DeleteImportProfile(r.Context(), profileID, userID); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to delete import profile", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Import profile deleted successfully",
+	})
+}
+
+func applyImportProfileDefaults(profile *models.ImportProfile) {
+	if profile.Timezone == "" {
+		profile.Timezone = defaultImportTimezone
+	}
+	if profile.Delimiter == "" {
+		profile.Delimiter = ","
+	}
+}
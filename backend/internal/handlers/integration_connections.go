@@ -0,0 +1,402 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/database"
+	"github.com/tradepulse/api/internal/integrations"
+	"github.com/tradepulse/api/internal/jobs"
+	"github.com/tradepulse/api/internal/middleware"
+	"github.com/tradepulse/api/internal/models"
+	"github.com/tradepulse/api/internal/secrets"
+)
+
+type IntegrationConnectionsHandler struct {
+	db      *database.DB
+	cipher  *secrets.Cipher
+	runner  *jobs.Runner
+	brokers *integrations.Registry
+}
+
+// NewIntegrationConnectionsHandler creates the handler. cipher is nil when no
+// credentials key is configured, in which case every endpoint reports 503.
+func NewIntegrationConnectionsHandler(db *database.DB, cipher *secrets.Cipher, runner *jobs.Runner, brokers *integrations.Registry) *IntegrationConnectionsHandler {
+	return &IntegrationConnectionsHandler{db: db, cipher: cipher, runner: runner, brokers: brokers}
+}
+
+type connectionInput struct {
+	Provider      models.IntegrationProvider `json:"provider"`
+	Name          string                     `json:"name"`
+	Site          string                     `json:"site"`
+	Username      string                     `json:"username"`
+	Password      string                     `json:"password"`
+	AccountID     *string                    `json:"account_id,omitempty"` // "" to sync every account
+	SyncEnabled   *bool                      `json:"sync_enabled,omitempty"`
+	SyncWeekdays  []int                      `json:"sync_weekdays,omitempty"`  // 0 = Sunday
+	SyncTime      string                     `json:"sync_time,omitempty"`      // HH:MM
+	SyncTimezone  string                     `json:"sync_timezone,omitempty"`  // IANA name
+	SyncedThrough *string                    `json:"synced_through,omitempty"` // YYYY-MM-DD, "" to clear
+}
+
+var (
+	defaultSyncWeekdays = []int{1, 2, 3, 4, 5}
+	defaultSyncTime     = "16:30"
+)
+
+// ListConnections handles GET /api/integrations/connections
+func (h *IntegrationConnectionsHandler) ListConnections(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	userID, _ := middleware.GetUserID(r)
+
+	connections, err := h.db.ListIntegrationConnections(r.Context(), userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch connections", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    connections,
+	})
+}
+
+// GetConnection handles GET /api/integrations/connections/{id}
+func (h *IntegrationConnectionsHandler) GetConnection(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	connection, ok := h.loadConnection(w, r)
+	if !ok {
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    connection,
+	})
+}
+
+// CreateConnection handles POST /api/integrations/connections
+func (h *IntegrationConnectionsHandler) CreateConnection(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	userID, _ := middleware.GetUserID(r)
+
+	var input connectionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if input.Provider == "" {
+		input.Provider = models.ProviderPropReports
+	}
+	provider, ok := h.brokers.Lookup(string(input.Provider))
+	if !ok {
+		sendError(w, http.StatusBadRequest, "Unsupported provider", nil)
+		return
+	}
+	if strings.TrimSpace(input.Name) == "" {
+		sendError(w, http.StatusBadRequest, "Name is required", nil)
+		return
+	}
+	credentials := integrations.Credentials{Site: input.Site, Username: input.Username, Password: input.Password}
+	if err := provider.ValidateCredentials(credentials); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	sealed, err := h.cipher.SealCredentials(userID, models.IntegrationCredentials{Password: input.Password})
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to encrypt credentials", err)
+		return
+	}
+
+	connection := models.IntegrationConnection{
+		UserID:               userID,
+		Provider:             provider.Provider,
+		Name:                 strings.TrimSpace(input.Name),
+		Site:                 input.Site,
+		Username:             input.Username,
+		EncryptedCredentials: sealed,
+		SyncWeekdays:         defaultSyncWeekdays,
+		SyncTime:             defaultSyncTime,
+		SyncTimezone:         defaultImportTimezone,
+	}
+	if input.AccountID != nil {
+		connection.AccountID = *input.AccountID
+	}
+
+	if err := applySchedule(&connection, input); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if err := h.db.CreateIntegrationConnection(r.Context(), &connection); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to create connection", err)
+		return
+	}
+
+	sendJSON(w, http.StatusCreated, map[string]interface{}{
+		"success": true,
+		"data":    connection,
+	})
+}
+
+// UpdateConnection handles PUT /api/integrations/connections/{id}
+// Fields left out of the body, the password included, keep their stored values.
+func (h *IntegrationConnectionsHandler) UpdateConnection(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	userID, _ := middleware.GetUserID(r)
+
+	connection, ok := h.loadConnection(w, r)
+	if !ok {
+		return
+	}
+
+	var input connectionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if name := strings.TrimSpace(input.Name); name != "" {
+		connection.Name = name
+	}
+	if input.Site != "" {
+		connection.Site = input.Site
+	}
+	if input.Username != "" {
+		connection.Username = input.Username
+	}
+	if input.AccountID != nil {
+		connection.AccountID = *input.AccountID
+	}
+
+	if input.Password != "" {
+		sealed, err := h.cipher.SealCredentials(userID, models.IntegrationCredentials{Password: input.Password})
+		if err != nil {
+			sendError(w, http.StatusInternalServerError, "Failed to encrypt credentials", err)
+			return
+		}
+		connection.EncryptedCredentials = sealed
+	}
+
+	if err := applySchedule(connection, input); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if err := h.db.UpdateIntegrationConnection(r.Context(), connection.ID, userID, connection); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to update connection", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    connection,
+	})
+}
+
+// DeleteConnection handles DELETE /api/integrations/connections/{id}
+func (h *IntegrationConnectionsHandler) DeleteConnection(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	userID, _ := middleware.GetUserID(r)
+	connectionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid connection ID", err)
+		return
+	}
+
+	if err := h.db.DeleteIntegrationConnection(r.Context(), connectionID, userID); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to delete connection", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Connection deleted successfully",
+	})
+}
+
+// TestConnection handles POST /api/integrations/connections/{id}/test
+// Logs in to the broker with the stored credentials and logs straight out again.
+func (h *IntegrationConnectionsHandler) TestConnection(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	connection, ok := h.loadConnection(w, r)
+	if !ok {
+		return
+	}
+
+	credentials, err := h.cipher.OpenCredentials(connection)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to read stored credentials", err)
+		return
+	}
+
+	broker, err := h.brokers.New(connection.Provider, integrations.Credentials{
+		Site:     connection.Site,
+		Username: connection.Username,
+		Password: credentials.Password,
+	})
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Unsupported provider", err)
+		return
+	}
+
+	if err := broker.Authenticate(r.Context()); err != nil {
+		sendJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"data": map[string]interface{}{
+				"ok":    false,
+				"error": err.Error(),
+			},
+		})
+		return
+	}
+	broker.Close()
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data": map[string]interface{}{
+			"ok": true,
+		},
+	})
+}
+
+// SyncConnection handles POST /api/integrations/connections/{id}/sync
+// Queues an import for the connection and returns the job. The optional body
+// carries from_date, to_date and account_id.
+func (h *IntegrationConnectionsHandler) SyncConnection(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	userID, _ := middleware.GetUserID(r)
+
+	connection, ok := h.loadConnection(w, r)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		AccountID string `json:"account_id"`
+		FromDate  string `json:"from_date"`
+		ToDate    string `json:"to_date"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			sendError(w, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+
+	payload := jobs.BrokerImportPayload{
+		ConnectionID: &connection.ID,
+		AccountID:    input.AccountID,
+		FromDate:     input.FromDate,
+		ToDate:       input.ToDate,
+	}
+
+	job, err := h.runner.Enqueue(r.Context(), userID, models.JobTypeBrokerImport, payload, nil)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to queue sync", err)
+		return
+	}
+
+	sendJSON(w, http.StatusAccepted, map[string]interface{}{
+		"success": true,
+		"data":    job,
+	})
+}
+
+// applySchedule copies the sync schedule fields that were sent onto the
+// connection, validates the result and works out the next scheduled run
+func applySchedule(connection *models.IntegrationConnection, input connectionInput) error {
+	if input.SyncEnabled != nil {
+		connection.SyncEnabled = *input.SyncEnabled
+	}
+	if input.SyncWeekdays != nil {
+		connection.SyncWeekdays = input.SyncWeekdays
+	}
+	if input.SyncTime != "" {
+		connection.SyncTime = input.SyncTime
+	}
+	if input.SyncTimezone != "" {
+		connection.SyncTimezone = input.SyncTimezone
+	}
+	if input.SyncedThrough != nil {
+		connection.SyncedThrough = nil
+		if *input.SyncedThrough != "" {
+			day, err := time.Parse("2006-01-02", *input.SyncedThrough)
+			if err != nil {
+				return fmt.Errorf("synced_through must be YYYY-MM-DD")
+			}
+			connection.SyncedThrough = &day
+		}
+	}
+
+	for _, day := range connection.SyncWeekdays {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("sync_weekdays must be between 0 (Sunday) and 6 (Saturday)")
+		}
+	}
+
+	next, err := jobs.NextSync(connection, time.Now())
+	if err != nil {
+		return err
+	}
+
+	connection.NextSyncAt = nil
+	if connection.SyncEnabled {
+		connection.NextSyncAt = &next
+	}
+
+	return nil
+}
+
+func (h *IntegrationConnectionsHandler) enabled(w http.ResponseWriter) bool {
+	if h.cipher == nil {
+		sendError(w, http.StatusServiceUnavailable, "Saved connections are not configured on this server", nil)
+		return false
+	}
+	return true
+}
+
+// loadConnection fetches the connection named in the URL, writing the error response when it cannot
+func (h *IntegrationConnectionsHandler) loadConnection(w http.ResponseWriter, r *http.Request) (*models.IntegrationConnection, bool) {
+	userID, _ := middleware.GetUserID(r)
+	connectionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid connection ID", err)
+		return nil, false
+	}
+
+	connection, err := h.db.GetIntegrationConnection(r.Context(), connectionID, userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch connection", err)
+		return nil, false
+	}
+	if connection == nil {
+		sendError(w, http.StatusNotFound, "Connection not found", nil)
+		return nil, false
+	}
+
+	return connection, true
+}
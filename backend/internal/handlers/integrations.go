@@ -5,75 +5,131 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/tradepulse/api/internal/integrations"
+	"github.com/tradepulse/api/internal/jobs"
+	"github.com/tradepulse/api/internal/middleware"
+	"github.com/tradepulse/api/internal/models"
 )
 
-type FetchPropReportsInput struct {
-	Site     string `json:"site"`
+type BrokerCredentialsInput struct {
+	Site     string `json:"site,omitempty"` // Required by brokers with one site per firm
 	Username string `json:"username"`
 	Password string `json:"password"`
-	FromDate string `json:"from_date,omitempty"` // Optional: YYYY-MM-DD format
-	ToDate   string `json:"to_date,omitempty"`   // Optional: YYYY-MM-DD format
 }
 
-// FetchPropReportsTrades fetches trades from PropReports API
-func FetchPropReportsTrades(logger *slog.Logger) http.HandlerFunc {
+type FetchBrokerTradesInput struct {
+	BrokerCredentialsInput
+	AccountID string `json:"account_id,omitempty"` // Optional: import a single broker account
+	FromDate  string `json:"from_date,omitempty"`  // Optional: YYYY-MM-DD format
+	ToDate    string `json:"to_date,omitempty"`    // Optional: YYYY-MM-DD format
+}
+
+func (i BrokerCredentialsInput) credentials() integrations.Credentials {
+	return integrations.Credentials{Site: i.Site, Username: i.Username, Password: i.Password}
+}
+
+// ListIntegrations lists the brokers that can be connected and what each supports
+func ListIntegrations(brokers *integrations.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeSuccess(w, http.StatusOK, brokers.Providers())
+	}
+}
+
+// TestBrokerCredentials logs in to the broker in the URL and straight out again,
+// without saving anything
+func TestBrokerCredentials(brokers *integrations.Registry, logger *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var input FetchPropReportsInput
+		provider, ok := lookupProvider(w, r, brokers)
+		if !ok {
+			return
+		}
+
+		var input BrokerCredentialsInput
 		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "Invalid request body")
 			return
 		}
 
-		if input.Site == "" || input.Username == "" || input.Password == "" {
-			writeError(w, http.StatusBadRequest, "MISSING_CREDENTIALS", "Site, username, and password are required")
+		if err := provider.ValidateCredentials(input.credentials()); err != nil {
+			writeError(w, http.StatusBadRequest, "MISSING_CREDENTIALS", err.Error())
 			return
 		}
 
-		// Fetch trades from PropReports
-		client := integrations.NewPropReportsClient(input.Site, input.Username, input.Password)
-		trades, err := client.FetchTrades(input.FromDate, input.ToDate)
+		broker, err := brokers.New(provider.Provider, input.credentials())
 		if err != nil {
-			logger.Error("Failed to fetch PropReports trades", "error", err, "site", input.Site, "username", input.Username)
-			writeError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch trades from PropReports: "+err.Error())
+			writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+			return
+		}
+
+		if err := broker.Authenticate(r.Context()); err != nil {
+			logger.Info("Broker login test failed", "provider", provider.Provider, "site", input.Site)
+			writeSuccess(w, http.StatusOK, map[string]interface{}{"ok": false, "error": err.Error()})
+			return
+		}
+		broker.Close()
+
+		writeSuccess(w, http.StatusOK, map[string]interface{}{"ok": true})
+	}
+}
+
+// FetchBrokerTrades queues a background import of trades from the broker in the
+// URL. A backfill can make one request per account per day, so it runs as a job;
+// the response carries the job ID and progress arrives over /api/ws. Trades are
+// saved through the idempotent import path, so repeating a fetch is safe.
+func FetchBrokerTrades(brokers *integrations.Registry, runner *jobs.Runner, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := middleware.GetUserID(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "User not authenticated")
+			return
+		}
+
+		provider, ok := lookupProvider(w, r, brokers)
+		if !ok {
+			return
+		}
+
+		var input FetchBrokerTradesInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "Invalid request body")
+			return
+		}
+
+		if err := provider.ValidateCredentials(input.credentials()); err != nil {
+			writeError(w, http.StatusBadRequest, "MISSING_CREDENTIALS", err.Error())
 			return
 		}
 
-		logger.Info("Successfully fetched trades from PropReports", "site", input.Site, "username", input.Username, "count", len(trades))
-
-		// Log sample of trades for debugging
-		if len(trades) > 0 {
-			logger.Info("=== Sample of Imported Trades ===")
-			sampleSize := 5
-			if len(trades) < sampleSize {
-				sampleSize = len(trades)
-			}
-			for i := 0; i < sampleSize; i++ {
-				t := trades[i]
-				logger.Info("Trade Sample",
-					"index", i+1,
-					"symbol", t.Symbol,
-					"type", t.TradeType,
-					"quantity", t.Quantity,
-					"entry_price", t.EntryPrice,
-					"exit_price", t.ExitPrice,
-					"opened_at", t.OpenedAt.Format("2006-01-02 15:04:05"),
-					"closed_at", func() string {
-						if t.ClosedAt != nil {
-							return t.ClosedAt.Format("2006-01-02 15:04:05")
-						}
-						return "OPEN"
-					}(),
-					"pnl", t.PnL,
-					"fees", t.Fees,
-				)
-			}
-			if len(trades) > sampleSize {
-				logger.Info("... and more", "remaining", len(trades)-sampleSize)
-			}
-			logger.Info("=================================")
+		payload := jobs.BrokerImportPayload{
+			Provider:  provider.Provider,
+			Site:      input.Site,
+			Username:  input.Username,
+			AccountID: input.AccountID,
+			FromDate:  input.FromDate,
+			ToDate:    input.ToDate,
+		}
+		secret := jobs.BrokerSecret{Password: input.Password}
+
+		job, err := runner.Enqueue(r.Context(), userID, models.JobTypeBrokerImport, payload, secret)
+		if err != nil {
+			logger.Error("Failed to queue broker import", "error", err, "provider", provider.Provider, "site", input.Site)
+			writeError(w, http.StatusInternalServerError, "QUEUE_ERROR", "Failed to queue broker import")
+			return
 		}
 
-		writeSuccess(w, http.StatusOK, trades)
+		logger.Info("Queued broker import", "job_id", job.ID, "provider", provider.Provider, "site", input.Site)
+
+		writeSuccess(w, http.StatusAccepted, job)
+	}
+}
+
+// lookupProvider resolves the {provider} URL parameter, writing a 404 when it is unknown
+func lookupProvider(w http.ResponseWriter, r *http.Request, brokers *integrations.Registry) (integrations.ProviderInfo, bool) {
+	provider, ok := brokers.Lookup(chi.URLParam(r, "provider"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Unknown broker integration")
+		return integrations.ProviderInfo{}, false
 	}
+	return provider, true
 }
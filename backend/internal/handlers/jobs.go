@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/database"
+	"github.com/tradepulse/api/internal/middleware"
+)
+
+// ListJobs returns the user's most recent background jobs
+func ListJobs(db *database.DB, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := middleware.GetUserID(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "User not authenticated")
+			return
+		}
+
+		limit := 50
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= 200 {
+				limit = parsed
+			}
+		}
+
+		jobs, err := db.ListJobs(r.Context(), userID, limit)
+		if err != nil {
+			logger.Error("Failed to list jobs", "error", err, "user_id", userID)
+			writeError(w, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to fetch jobs")
+			return
+		}
+
+		writeSuccess(w, http.StatusOK, jobs)
+	}
+}
+
+// GetJob returns a single background job with its progress and result
+func GetJob(db *database.DB, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := middleware.GetUserID(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "User not authenticated")
+			return
+		}
+
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid job ID")
+			return
+		}
+
+		job, err := db.GetJob(r.Context(), id, userID)
+		if err != nil {
+			logger.Error("Failed to get job", "error", err, "job_id", id)
+			writeError(w, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to fetch job")
+			return
+		}
+		if job == nil {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Job not found")
+			return
+		}
+
+		writeSuccess(w, http.StatusOK, job)
+	}
+}
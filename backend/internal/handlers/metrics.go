@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/database"
+	"github.com/tradepulse/api/internal/metrics"
+	"github.com/tradepulse/api/internal/middleware"
+)
+
+// GetSummaryMetrics handles GET /api/metrics/summary
+// Computes the performance statistics of the closed trades matching the same
+// filters as GET /api/trades, overall and split into long and short trades.
+func GetSummaryMetrics(db *database.DB, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := middleware.GetUserID(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "User not authenticated")
+			return
+		}
+
+		filters, err := parseTradeFilters(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+
+		trades, err := db.ListTradeResults(r.Context(), userID, filters)
+		if err != nil {
+			logger.Error("Failed to list trades for metrics", "error", err)
+			writeError(w, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to compute metrics")
+			return
+		}
+
+		writeSuccess(w, http.StatusOK, metrics.Summarize(trades))
+	}
+}
+
+// GetMetricsBySymbol handles GET /api/metrics/by-symbol
+// Aggregates the closed trades matching the same filters as GET /api/trades by
+// symbol. "sort" names the column to sort by (default net_pnl) and "order" is
+// asc or desc (default desc); "min_trades" leaves out symbols traded fewer times.
+func GetMetricsBySymbol(db *database.DB, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := middleware.GetUserID(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "User not authenticated")
+			return
+		}
+
+		filters, err := parseTradeFilters(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+
+		query := r.URL.Query()
+		opts := database.SymbolMetricsOptions{
+			SortBy:     query.Get("sort"),
+			Descending: true,
+		}
+		if opts.SortBy != "" && !database.ValidSymbolMetricsSort(opts.SortBy) {
+			writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid sort column")
+			return
+		}
+		switch query.Get("order") {
+		case "", "desc":
+		case "asc":
+			opts.Descending = false
+		default:
+			writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", "order must be asc or desc")
+			return
+		}
+		if value := query.Get("min_trades"); value != "" {
+			opts.MinTrades, err = strconv.Atoi(value)
+			if err != nil || opts.MinTrades < 0 {
+				writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", "min_trades must be a non-negative integer")
+				return
+			}
+		}
+
+		results, err := db.ListSymbolMetrics(r.Context(), userID, filters, opts)
+		if err != nil {
+			logger.Error("Failed to aggregate symbol metrics", "error", err)
+			writeError(w, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to compute metrics")
+			return
+		}
+
+		writeSuccess(w, http.StatusOK, results)
+	}
+}
+
+// GetDailyPerformance handles GET /api/metrics/daily
+// Builds the P&L calendar and equity curve of the closed trades matching the
+// same filters as GET /api/trades. Trades are bucketed by the day they closed
+// in the user's time zone and rolled up by "period" (day, week or month). The
+// curve starts from "starting_equity", which defaults to the starting balance
+// of the account selected by "account_id" and otherwise to zero.
+func GetDailyPerformance(db *database.DB, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := middleware.GetUserID(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "User not authenticated")
+			return
+		}
+
+		filters, err := parseTradeFilters(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+
+		query := r.URL.Query()
+		period, err := metrics.ParsePeriod(query.Get("period"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+
+		timezone, err := userTimezone(r, db, userID)
+		if err != nil {
+			logger.Error("Failed to get user timezone", "error", err)
+			writeError(w, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to compute metrics")
+			return
+		}
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid timezone")
+			return
+		}
+
+		var startingEquity float64
+		if value := query.Get("starting_equity"); value != "" {
+			startingEquity, err = strconv.ParseFloat(value, 64)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", "starting_equity must be a number")
+				return
+			}
+		} else if filters.AccountID != "" {
+			account, err := db.GetAccount(r.Context(), uuid.MustParse(filters.AccountID), userID)
+			if err != nil {
+				logger.Error("Failed to get account", "error", err)
+				writeError(w, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to compute metrics")
+				return
+			}
+			if account != nil {
+				startingEquity = account.StartingBalance
+			}
+		}
+
+		trades, err := db.ListTradeResults(r.Context(), userID, filters)
+		if err != nil {
+			logger.Error("Failed to list trades for metrics", "error", err)
+			writeError(w, http.StatusInternalServerError, "DATABASE_ERROR", "Failed to compute metrics")
+			return
+		}
+
+		writeSuccess(w, http.StatusOK, metrics.DailyPerformance(trades, loc, period, startingEquity))
+	}
+}
+
+// userTimezone returns the time zone days are counted in: the "timezone" query
+// parameter, else the one saved in the user's preferences, else America/New_York
+func userTimezone(r *http.Request, db *database.DB, userID uuid.UUID) (string, error) {
+	if timezone := r.URL.Query().Get("timezone"); timezone != "" {
+		return timezone, nil
+	}
+
+	timezone, err := db.GetUserTimezone(r.Context(), userID)
+	if err != nil {
+		return "", err
+	}
+	if timezone == "" {
+		timezone = defaultImportTimezone
+	}
+	return timezone, nil
+}
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -52,26 +54,10 @@ func NewTradesHandler(db *database.DB, bus *notifications.Bus) *TradesHandler {
 func (h *TradesHandler) ListTrades(w http.ResponseWriter, r *http.Request) {
 	userID := getUserID(r)
 
-	// Parse query parameters
-	filters := database.TradeFilters{
-		Symbol:    r.URL.Query().Get("symbol"),
-		TradeType: r.URL.Query().Get("trade_type"),
-		Status:    r.URL.Query().Get("status"),
-		StartDate: r.URL.Query().Get("start_date"),
-		EndDate:   r.URL.Query().Get("end_date"),
-		Strategy:  r.URL.Query().Get("strategy"),
-	}
-
-	// Parse P&L filters
-	if minPnLStr := r.URL.Query().Get("min_pnl"); minPnLStr != "" {
-		if minPnL, err := strconv.ParseFloat(minPnLStr, 64); err == nil {
-			filters.MinPnL = &minPnL
-		}
-	}
-	if maxPnLStr := r.URL.Query().Get("max_pnl"); maxPnLStr != "" {
-		if maxPnL, err := strconv.ParseFloat(maxPnLStr, 64); err == nil {
-			filters.MaxPnL = &maxPnL
-		}
+	filters, err := parseTradeFilters(r)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err.Error(), err)
+		return
 	}
 
 	// Parse pagination
@@ -119,6 +105,48 @@ func (h *TradesHandler) ListTrades(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// parseTradeFilters reads the trade selection shared by the trade list and
+// metrics endpoints from the query string
+func parseTradeFilters(r *http.Request) (database.TradeFilters, error) {
+	query := r.URL.Query()
+	filters := database.TradeFilters{
+		Symbol:    query.Get("symbol"),
+		TradeType: query.Get("trade_type"),
+		Status:    query.Get("status"),
+		StartDate: query.Get("start_date"),
+		EndDate:   query.Get("end_date"),
+		Strategy:  query.Get("strategy"),
+	}
+
+	if batchID := query.Get("import_batch_id"); batchID != "" {
+		if _, err := uuid.Parse(batchID); err != nil {
+			return filters, fmt.Errorf("invalid import_batch_id")
+		}
+		filters.ImportBatchID = batchID
+	}
+
+	if accountID := query.Get("account_id"); accountID != "" {
+		if _, err := uuid.Parse(accountID); err != nil {
+			return filters, fmt.Errorf("invalid account_id")
+		}
+		filters.AccountID = accountID
+	}
+
+	// Parse P&L filters
+	if minPnLStr := query.Get("min_pnl"); minPnLStr != "" {
+		if minPnL, err := strconv.ParseFloat(minPnLStr, 64); err == nil {
+			filters.MinPnL = &minPnL
+		}
+	}
+	if maxPnLStr := query.Get("max_pnl"); maxPnLStr != "" {
+		if maxPnL, err := strconv.ParseFloat(maxPnLStr, 64); err == nil {
+			filters.MaxPnL = &maxPnL
+		}
+	}
+
+	return filters, nil
+}
+
 // GetTrade handles GET /api/trades/{id}
 func (h *TradesHandler) GetTrade(w http.ResponseWriter, r *http.Request) {
 	userID := getUserID(r)
@@ -169,6 +197,19 @@ func (h *TradesHandler) CreateTrade(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if trade.StopLoss != nil && *trade.StopLoss <= 0 {
+		sendError(w, http.StatusBadRequest, "Invalid stop loss", nil)
+		return
+	}
+
+	if ok, err := checkAccount(r, h.db, trade.AccountID, userID); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch account", err)
+		return
+	} else if !ok {
+		sendError(w, http.StatusBadRequest, "Account not found", nil)
+		return
+	}
+
 	// Create trade
 	if err := h.db.CreateTrade(r.Context(), &trade); err != nil {
 		sendError(w, http.StatusInternalServerError, "Failed to create trade", err)
@@ -202,8 +243,19 @@ func (h *TradesHandler) UpdateTrade(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var trade models.Trade
-	if err := json.NewDecoder(r.Body).Decode(&trade); err != nil {
+	// Decode the body over the stored trade so that fields it leaves out, such
+	// as the multiplier the P&L trigger depends on, keep their values
+	trade, err := h.db.GetTrade(r.Context(), tradeID, userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch trade", err)
+		return
+	}
+	if trade == nil {
+		sendError(w, http.StatusNotFound, "Trade not found", nil)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(trade); err != nil {
 		sendError(w, http.StatusBadRequest, "Invalid request body", err)
 		return
 	}
@@ -220,8 +272,21 @@ func (h *TradesHandler) UpdateTrade(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if trade.StopLoss != nil && *trade.StopLoss <= 0 {
+		sendError(w, http.StatusBadRequest, "Invalid stop loss", nil)
+		return
+	}
+
+	if ok, err := checkAccount(r, h.db, trade.AccountID, userID); err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch account", err)
+		return
+	} else if !ok {
+		sendError(w, http.StatusBadRequest, "Account not found", nil)
+		return
+	}
+
 	// Update trade
-	if err := h.db.UpdateTrade(r.Context(), tradeID, userID, &trade); err != nil {
+	if err := h.db.UpdateTrade(r.Context(), tradeID, userID, trade); err != nil {
 		sendError(w, http.StatusInternalServerError, "Failed to update trade", err)
 		return
 	}
@@ -334,3 +399,65 @@ func (h *TradesHandler) RemoveTagFromTrade(w http.ResponseWriter, r *http.Reques
 		"message": "Tag removed from trade successfully",
 	})
 }
+
+// ListTradeExecutions handles GET /api/trades/{id}/executions
+func (h *TradesHandler) ListTradeExecutions(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	tradeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid trade ID", err)
+		return
+	}
+
+	executions, err := h.db.ListExecutionsByTradeID(r.Context(), tradeID, userID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to fetch executions", err)
+		return
+	}
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success":       true,
+		"data":          executions,
+		"fee_breakdown": models.SumFees(executions),
+	})
+}
+
+// RebuildTrade handles POST /api/trades/{id}/rebuild
+func (h *TradesHandler) RebuildTrade(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	tradeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid trade ID", err)
+		return
+	}
+
+	trade, err := h.db.RebuildTradeFromExecutions(r.Context(), tradeID, userID)
+	if errors.Is(err, database.ErrTradeNotFound) {
+		sendError(w, http.StatusNotFound, "Trade not found", nil)
+		return
+	}
+	if errors.Is(err, database.ErrNoExecutions) {
+		sendError(w, http.StatusBadRequest, "Trade has no executions to rebuild from", nil)
+		return
+	}
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to rebuild trade", err)
+		return
+	}
+
+	h.bus.Publish(
+		notifications.NotificationTypeTradeUpdated,
+		userID,
+		"Trade Rebuilt",
+		"Trade recalculated from its executions",
+		map[string]interface{}{
+			"id":     trade.ID,
+			"symbol": trade.Symbol,
+		},
+	)
+
+	sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data":    trade,
+	})
+}
@@ -0,0 +1,39 @@
+package importers
+
+import "time"
+
+type CashActivityType string
+
+const (
+	CashDeposit    CashActivityType = "DEPOSIT"
+	CashWithdrawal CashActivityType = "WITHDRAWAL"
+	CashDividend   CashActivityType = "DIVIDEND"
+	CashInterest   CashActivityType = "INTEREST"
+	CashFee        CashActivityType = "FEE"
+	CashOther      CashActivityType = "OTHER"
+)
+
+// CashActivity is a cash movement in a broker statement that is not a trade
+type CashActivity struct {
+	Type        CashActivityType `json:"type"`
+	Amount      float64          `json:"amount"` // positive adds to the account, negative takes from it
+	Description string           `json:"description,omitempty"`
+	Reference   string           `json:"reference,omitempty"`
+	OccurredAt  time.Time        `json:"occurred_at"`
+	Account     string           `json:"account,omitempty"` // broker account number, when the statement names one
+}
+
+// setCashAccount records the account a statement's cash activity belongs to
+func setCashAccount(cash []CashActivity, account string) {
+	for i := range cash {
+		cash[i].Account = account
+	}
+}
+
+// fundingActivity classifies a transfer in or out of the account by its sign
+func fundingActivity(amount float64) CashActivityType {
+	if amount < 0 {
+		return CashWithdrawal
+	}
+	return CashDeposit
+}
@@ -0,0 +1,171 @@
+package importers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// DASOrderLog is the result of parsing a DAS Trader order-event log
+type DASOrderLog struct {
+	Executions []models.Execution
+	Orders     []models.OrderEvent // canceled and rejected orders
+}
+
+// dasColumns are the columns every DAS order-event log must have
+var dasColumns = []string{"Event", "B/S", "Symbol", "Shares", "Price", "Route", "Time", "Account", "Note"}
+
+// ParseDASOrderLog parses a DAS Trader (Ocean One) order-event log. Each row is an
+// order event (Sending, Accept, Execute, Canceling, Canceled, Send_Rej); only
+// Execute rows become executions, and final Canceled and rejected rows are kept
+// as order events. The log has no date column, so times are placed on tradeDate
+// in its location.
+func ParseDASOrderLog(r io.Reader, tradeDate time.Time) (*DASOrderLog, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.FieldsPerRecord = -1 // Rows end with a trailing comma
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range dasColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q: not a DAS order-event log", name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i := columns[name]
+		if i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	result := &DASOrderLog{
+		Executions: make([]models.Execution, 0),
+		Orders:     make([]models.OrderEvent, 0),
+	}
+
+	line := 1
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		event := field(record, "Event")
+		var status models.OrderStatus
+		switch strings.ToLower(event) {
+		case "execute":
+		case "canceled", "cancelled":
+			status = models.OrderCanceled
+		case "send_rej", "rejected", "reject":
+			status = models.OrderRejected
+		default:
+			// Sending, Accept and Canceling are intermediate states
+			continue
+		}
+
+		side, ok := parseDASSide(field(record, "B/S"))
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown side %q", line, field(record, "B/S"))
+		}
+
+		quantity, err := strconv.ParseFloat(field(record, "Shares"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid shares %q", line, field(record, "Shares"))
+		}
+
+		price, err := strconv.ParseFloat(field(record, "Price"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid price %q", line, field(record, "Price"))
+		}
+
+		clock, err := time.Parse("15:04:05", field(record, "Time"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid time %q", line, field(record, "Time"))
+		}
+		at := time.Date(tradeDate.Year(), tradeDate.Month(), tradeDate.Day(),
+			clock.Hour(), clock.Minute(), clock.Second(), 0, tradeDate.Location())
+
+		symbol := strings.ToUpper(field(record, "Symbol"))
+
+		if status == "" {
+			result.Executions = append(result.Executions, models.Execution{
+				Account:    field(record, "Account"),
+				Symbol:     symbol,
+				Side:       side,
+				Quantity:   quantity,
+				Price:      price,
+				ExecutedAt: at,
+				Route:      field(record, "Route"),
+			})
+			continue
+		}
+
+		result.Orders = append(result.Orders, models.OrderEvent{
+			Account:  field(record, "Account"),
+			Symbol:   symbol,
+			Side:     side,
+			Quantity: quantity,
+			Price:    price,
+			Route:    field(record, "Route"),
+			Status:   status,
+			Note:     field(record, "Note"),
+			EventAt:  at,
+		})
+	}
+
+	// DAS writes the newest event first. Reverse so executions that share a
+	// timestamp stay in the order they happened once sorted by time.
+	if isNewestFirst(result.Executions) {
+		for i, j := 0, len(result.Executions)-1; i < j; i, j = i+1, j-1 {
+			result.Executions[i], result.Executions[j] = result.Executions[j], result.Executions[i]
+		}
+	}
+
+	return result, nil
+}
+
+// isNewestFirst reports whether consecutive executions on the same symbol mostly
+// go back in time
+func isNewestFirst(executions []models.Execution) bool {
+	var ascending, descending int
+	for i := 1; i < len(executions); i++ {
+		if executions[i].Symbol != executions[i-1].Symbol {
+			continue
+		}
+		if executions[i].ExecutedAt.Before(executions[i-1].ExecutedAt) {
+			descending++
+		} else if executions[i].ExecutedAt.After(executions[i-1].ExecutedAt) {
+			ascending++
+		}
+	}
+	return descending > ascending
+}
+
+func parseDASSide(value string) (models.ExecutionSide, bool) {
+	switch strings.ToUpper(value) {
+	case "BUY", "B":
+		return models.SideBuy, true
+	case "SELL", "S", "SHORT", "SS":
+		return models.SideSell, true
+	}
+	return "", false
+}
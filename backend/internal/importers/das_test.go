@@ -0,0 +1,51 @@
+package importers
+
+import (
+	"testing"
+	"time"
+)
+
+const dasFixture = "Ocean One 2025-10-03.csv"
+
+func TestParseDASOrderLogGolden(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	// The log has no dates; the trade date comes from the file name
+	tradeDate, ok := TradeDateFromFilename(dasFixture, loc)
+	if !ok {
+		t.Fatalf("no trade date found in %q", dasFixture)
+	}
+
+	orderLog, err := ParseDASOrderLog(openFixture(t, "das", dasFixture), tradeDate)
+	if err != nil {
+		t.Fatalf("ParseDASOrderLog: %v", err)
+	}
+
+	if len(orderLog.Executions) != 5 || len(orderLog.Orders) != 2 {
+		t.Errorf("executions, orders = %d, %d; want 5, 2", len(orderLog.Executions), len(orderLog.Orders))
+	}
+	for _, e := range orderLog.Executions {
+		if e.ExecutedAt.Format("2006-01-02 -0700") != "2025-10-03 -0400" {
+			t.Errorf("%s fill executed at %v, want on 2025-10-03 New York time", e.Symbol, e.ExecutedAt)
+		}
+	}
+
+	trades := BuildTrades(orderLog.Executions, nil)
+	unlinked := LinkOrders(trades, orderLog.Orders)
+	if len(trades) != 2 {
+		t.Fatalf("trades = %d, want the AAPL long and the TSLA short", len(trades))
+	}
+	if len(trades[0].Orders) != 1 || len(trades[1].Orders) != 0 {
+		t.Errorf("linked orders = %d on %s, %d on %s; want the canceled AAPL sell on AAPL",
+			len(trades[0].Orders), trades[0].Symbol, len(trades[1].Orders), trades[1].Symbol)
+	}
+	// No NVDA order filled, so the rejected one has no trade to join
+	if len(unlinked) != 1 || unlinked[0].Symbol != "NVDA" {
+		t.Errorf("unlinked orders = %+v, want the rejected NVDA buy", unlinked)
+	}
+
+	assertGolden(t, "das/ocean_one", trades)
+}
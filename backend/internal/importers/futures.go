@@ -0,0 +1,142 @@
+package importers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FuturesContract is a futures contract code broken into its parts
+type FuturesContract struct {
+	Root       string     `json:"root"`
+	Month      time.Month `json:"month"`
+	Year       int        `json:"year"`
+	PointValue float64    `json:"point_value"` // dollars per one point move of one contract
+}
+
+// futuresPointValues are the dollar values of a one point move for the contracts
+// traded on the platforms imported here, by root symbol
+var futuresPointValues = map[string]float64{
+	// Equity indexes
+	"ES": 50, "MES": 5, "NQ": 20, "MNQ": 2, "YM": 5, "MYM": 0.5,
+	"RTY": 50, "M2K": 5, "EMD": 100, "NKD": 5,
+	// Energy
+	"CL": 1000, "MCL": 100, "QM": 500, "NG": 10000, "QG": 2500, "RB": 42000, "HO": 42000,
+	// Metals
+	"GC": 100, "MGC": 10, "SI": 5000, "SIL": 1000, "HG": 25000, "MHG": 2500, "PL": 50, "PA": 100,
+	// Interest rates
+	"ZB": 1000, "UB": 1000, "ZN": 1000, "TN": 1000, "ZF": 1000, "ZT": 2000,
+	// Agriculture and livestock
+	"ZC": 50, "ZS": 50, "ZW": 50, "ZM": 100, "ZL": 600, "LE": 400, "HE": 400, "GF": 500,
+	// Currencies
+	"6E": 125000, "M6E": 12500, "6J": 12500000, "6B": 62500, "6A": 100000, "6C": 100000, "6S": 125000,
+	// Crypto and volatility
+	"BTC": 5, "MBT": 0.1, "ETH": 50, "MET": 0.1, "VX": 1000,
+}
+
+// futuresMonthCodes are the exchange month letters, January to December
+const futuresMonthCodes = "FGHJKMNQUVXZ"
+
+var (
+	// Exchange codes such as "ESZ5", "MNQH26" or thinkorswim's "/ESZ25"
+	futuresCodePattern = regexp.MustCompile(`^/?([A-Z0-9]{1,4}?)([FGHJKMNQUVXZ])(\d{1,2})$`)
+	// NinjaTrader instrument names such as "ES 12-25"
+	futuresNamePattern = regexp.MustCompile(`^([A-Z0-9]{1,4}) (\d{2})-(\d{2})$`)
+)
+
+// ParseFuturesContract reads a contract code such as "ESZ5" or "ES 12-25".
+// Single-digit years are placed in the decade that makes the contract current
+// at tradedAt. It returns false when the code is not shaped like a futures
+// contract, and an error when it is but the root is not a known contract.
+func ParseFuturesContract(code string, tradedAt time.Time) (FuturesContract, bool, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	var contract FuturesContract
+	if m := futuresNamePattern.FindStringSubmatch(code); m != nil {
+		month, _ := strconv.Atoi(m[2])
+		year, _ := strconv.Atoi(m[3])
+		if month < 1 || month > 12 {
+			return FuturesContract{}, false, nil
+		}
+		contract = FuturesContract{Root: m[1], Month: time.Month(month), Year: 2000 + year}
+	} else if m := futuresCodePattern.FindStringSubmatch(code); m != nil {
+		contract = FuturesContract{
+			Root:  m[1],
+			Month: time.Month(strings.Index(futuresMonthCodes, m[2]) + 1),
+			Year:  contractYear(m[3], tradedAt),
+		}
+	} else {
+		return FuturesContract{}, false, nil
+	}
+
+	pointValue, ok := futuresPointValues[contract.Root]
+	if !ok {
+		return FuturesContract{}, true, fmt.Errorf("unknown futures contract %q", code)
+	}
+	contract.PointValue = pointValue
+
+	return contract, true, nil
+}
+
+// Symbol returns the contract's exchange code, such as "ESZ5"
+func (c FuturesContract) Symbol() string {
+	if c.Month < time.January || c.Month > time.December {
+		return c.Root
+	}
+	return fmt.Sprintf("%s%c%d", c.Root, futuresMonthCodes[c.Month-1], c.Year%10)
+}
+
+// contractYear expands a one or two digit contract year. A contract can only
+// trade before it expires, so a single digit is taken as the first year ending
+// in it from the year before tradedAt onwards.
+func contractYear(digits string, tradedAt time.Time) int {
+	n, _ := strconv.Atoi(digits)
+	if len(digits) == 2 {
+		return 2000 + n
+	}
+
+	year := tradedAt.Year() - tradedAt.Year()%10 + n
+	if year < tradedAt.Year()-1 {
+		year += 10
+	}
+	return year
+}
+
+// futuresInstrument returns the symbol and multiplier for an instrument on a
+// futures platform. Anything not shaped like a futures contract, such as a
+// stock or currency pair, keeps its name and a multiplier of 1.
+func futuresInstrument(instrument string, tradedAt time.Time) (string, float64, error) {
+	contract, ok, err := ParseFuturesContract(instrument, tradedAt)
+	if err != nil {
+		return "", 0, err
+	}
+	if !ok {
+		return strings.ToUpper(strings.TrimSpace(instrument)), 1, nil
+	}
+	return contract.Symbol(), contract.PointValue, nil
+}
+
+// futuresTimeLayouts are the timestamp formats written by futures platform exports
+var futuresTimeLayouts = []string{
+	"1/2/2006 3:04:05 PM",
+	"1/2/2006 15:04:05",
+	"1/2/2006 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// parseFuturesTime reads a platform timestamp in loc, unless it carries its own zone
+func parseFuturesTime(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if at, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return at, nil
+	}
+	for _, layout := range futuresTimeLayouts {
+		if at, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return at, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
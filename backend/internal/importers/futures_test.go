@@ -0,0 +1,46 @@
+package importers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFuturesContract(t *testing.T) {
+	tradedAt := time.Date(2025, 12, 1, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		code   string
+		want   string
+		year   int
+		points float64
+	}{
+		{"ESZ5", "ESZ5", 2025, 50},
+		{"/ESZ25", "ESZ5", 2025, 50},
+		{"MNQH26", "MNQH6", 2026, 2},
+		{"ES 03-26", "ESH6", 2026, 50},
+		{"6EM6", "6EM6", 2026, 125000},
+		// A contract a year past expiry is still read in this decade
+		{"CLZ4", "CLZ4", 2024, 1000},
+		// Older than that, a single digit year is taken as the next decade
+		{"CLZ3", "CLZ3", 2033, 1000},
+	}
+
+	for _, tt := range tests {
+		contract, ok, err := ParseFuturesContract(tt.code, tradedAt)
+		if err != nil || !ok {
+			t.Errorf("ParseFuturesContract(%q) = %v, %v; want a contract", tt.code, ok, err)
+			continue
+		}
+		if contract.Symbol() != tt.want || contract.Year != tt.year || contract.PointValue != tt.points {
+			t.Errorf("ParseFuturesContract(%q) = %s %d at %v a point, want %s %d at %v",
+				tt.code, contract.Symbol(), contract.Year, contract.PointValue, tt.want, tt.year, tt.points)
+		}
+	}
+
+	if _, ok, err := ParseFuturesContract("AAPL", tradedAt); ok || err != nil {
+		t.Errorf("ParseFuturesContract(\"AAPL\") = %v, %v; want not a contract", ok, err)
+	}
+	if _, ok, err := ParseFuturesContract("QQZ5", tradedAt); !ok || err == nil {
+		t.Errorf("ParseFuturesContract(\"QQZ5\") = %v, %v; want an unknown contract error", ok, err)
+	}
+}
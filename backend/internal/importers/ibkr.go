@@ -0,0 +1,254 @@
+package importers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// IBKRFlexStatement is the result of parsing an Interactive Brokers Flex Query report
+type IBKRFlexStatement struct {
+	Accounts   []string
+	FromDate   string // YYYY-MM-DD, when the report states its period
+	ToDate     string
+	Executions []models.Execution
+	Skipped    int // order and lot summaries, currency conversions and canceled trades
+}
+
+type ibkrFlexQueryResponse struct {
+	XMLName    xml.Name            `xml:"FlexQueryResponse"`
+	Statements []ibkrFlexStatement `xml:"FlexStatements>FlexStatement"`
+}
+
+type ibkrFlexStatement struct {
+	AccountID string      `xml:"accountId,attr"`
+	FromDate  string      `xml:"fromDate,attr"`
+	ToDate    string      `xml:"toDate,attr"`
+	Trades    []ibkrTrade `xml:"Trades>Trade"`
+}
+
+// ibkrTrade holds the attributes of a <Trade> element that are used here. Flex
+// queries only include the fields selected when the query was set up, so any
+// of them may be missing.
+type ibkrTrade struct {
+	AccountID          string `xml:"accountId,attr"`
+	AssetCategory      string `xml:"assetCategory,attr"`
+	Symbol             string `xml:"symbol,attr"`
+	Multiplier         string `xml:"multiplier,attr"`
+	TradeID            string `xml:"tradeID,attr"`
+	OrigTradeID        string `xml:"origTradeID,attr"`
+	IBExecID           string `xml:"ibExecID,attr"`
+	IBOrderID          string `xml:"ibOrderID,attr"`
+	DateTime           string `xml:"dateTime,attr"`
+	TradeDate          string `xml:"tradeDate,attr"`
+	TradeTime          string `xml:"tradeTime,attr"`
+	BuySell            string `xml:"buySell,attr"`
+	Quantity           string `xml:"quantity,attr"`
+	TradePrice         string `xml:"tradePrice,attr"`
+	IBCommission       string `xml:"ibCommission,attr"`
+	Taxes              string `xml:"taxes,attr"`
+	FifoPnlRealized    string `xml:"fifoPnlRealized,attr"`
+	OpenCloseIndicator string `xml:"openCloseIndicator,attr"`
+	Exchange           string `xml:"exchange,attr"`
+	LevelOfDetail      string `xml:"levelOfDetail,attr"`
+}
+
+var (
+	ibkrDateLayouts = []string{"20060102", "2006-01-02", "01/02/2006"}
+	ibkrTimeLayouts = []string{"150405", "15:04:05"}
+)
+
+// ParseIBKRFlex parses an Interactive Brokers Flex Query XML report. Each
+// execution-level <Trade> element becomes an execution; commissions and taxes
+// become fees, and IBKR's FIFO realized P&L is kept on closing executions so it
+// can be reconciled with the P&L calculated here. Times carry no zone and are
+// read in loc.
+func ParseIBKRFlex(r io.Reader, loc *time.Location) (*IBKRFlexStatement, error) {
+	var response ibkrFlexQueryResponse
+	if err := xml.NewDecoder(r).Decode(&response); err != nil {
+		return nil, fmt.Errorf("not an IBKR Flex Query report: %w", err)
+	}
+	if len(response.Statements) == 0 {
+		return nil, fmt.Errorf("report has no FlexStatement elements")
+	}
+
+	result := &IBKRFlexStatement{
+		Accounts:   make([]string, 0),
+		Executions: make([]models.Execution, 0),
+	}
+
+	// A canceled trade is reported as the original and a cancellation pointing at it
+	canceled := make(map[string]bool)
+	for _, statement := range response.Statements {
+		for _, trade := range statement.Trades {
+			if isIBKRCancellation(trade) && trade.OrigTradeID != "" {
+				canceled[trade.OrigTradeID] = true
+			}
+		}
+	}
+
+	for i, statement := range response.Statements {
+		if statement.AccountID != "" {
+			result.Accounts = append(result.Accounts, statement.AccountID)
+		}
+		if from, ok := parseIBKRDate(statement.FromDate); ok && (result.FromDate == "" || from < result.FromDate) {
+			result.FromDate = from
+		}
+		if to, ok := parseIBKRDate(statement.ToDate); ok && to > result.ToDate {
+			result.ToDate = to
+		}
+
+		for j, trade := range statement.Trades {
+			if !isIBKRExecution(trade) || isIBKRCancellation(trade) || canceled[trade.TradeID] {
+				result.Skipped++
+				continue
+			}
+
+			execution, err := ibkrExecution(trade, statement.AccountID, loc)
+			if err != nil {
+				return nil, fmt.Errorf("statement %d, trade %d: %w", i+1, j+1, err)
+			}
+			result.Executions = append(result.Executions, execution)
+		}
+	}
+
+	return result, nil
+}
+
+func ibkrExecution(trade ibkrTrade, statementAccount string, loc *time.Location) (models.Execution, error) {
+	side, ok := parseIBKRSide(trade.BuySell)
+	if !ok {
+		return models.Execution{}, fmt.Errorf("unknown buySell %q", trade.BuySell)
+	}
+
+	quantity, err := parseIBKRNumber(trade.Quantity)
+	if err != nil || quantity == 0 {
+		return models.Execution{}, fmt.Errorf("invalid quantity %q", trade.Quantity)
+	}
+
+	price, err := parseIBKRNumber(trade.TradePrice)
+	if err != nil {
+		return models.Execution{}, fmt.Errorf("invalid tradePrice %q", trade.TradePrice)
+	}
+
+	at, err := parseIBKRTime(trade, loc)
+	if err != nil {
+		return models.Execution{}, err
+	}
+
+	multiplier := 1.0
+	if trade.Multiplier != "" {
+		multiplier, err = parseIBKRNumber(trade.Multiplier)
+		if err != nil || multiplier <= 0 {
+			return models.Execution{}, fmt.Errorf("invalid multiplier %q", trade.Multiplier)
+		}
+	}
+
+	commission, _ := parseIBKRNumber(trade.IBCommission)
+	taxes, _ := parseIBKRNumber(trade.Taxes)
+
+	account := trade.AccountID
+	if account == "" {
+		account = statementAccount
+	}
+
+	fillID := trade.IBExecID
+	if fillID == "" {
+		fillID = trade.TradeID
+	}
+
+	execution := models.Execution{
+		Account:    account,
+		Symbol:     strings.ToUpper(strings.Join(strings.Fields(trade.Symbol), "")),
+		Side:       side,
+		Quantity:   math.Abs(quantity),
+		Price:      price,
+		ExecutedAt: at,
+		Route:      trade.Exchange,
+		OrderID:    trade.IBOrderID,
+		FillID:     fillID,
+		Commission: debit(commission),
+		MiscFee:    debit(taxes),
+		Multiplier: multiplier,
+	}
+
+	// Realized P&L is only meaningful on fills that close a position
+	if strings.Contains(strings.ToUpper(trade.OpenCloseIndicator), "C") {
+		if pnl, err := parseIBKRNumber(trade.FifoPnlRealized); err == nil {
+			execution.RealizedPnL = &pnl
+		}
+	}
+
+	return execution, nil
+}
+
+// isIBKRExecution reports whether a <Trade> element is a single fill. Queries can
+// also include per-order and closed-lot rows that repeat the same fills, and
+// currency conversions, which are not trades.
+func isIBKRExecution(trade ibkrTrade) bool {
+	if trade.LevelOfDetail != "" && !strings.EqualFold(trade.LevelOfDetail, "EXECUTION") {
+		return false
+	}
+	return !strings.EqualFold(trade.AssetCategory, "CASH")
+}
+
+// isIBKRCancellation reports whether the row cancels an earlier trade, such as "SELL (Ca.)"
+func isIBKRCancellation(trade ibkrTrade) bool {
+	return strings.Contains(trade.BuySell, "(Ca.)")
+}
+
+func parseIBKRSide(value string) (models.ExecutionSide, bool) {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "BUY", "BOT":
+		return models.SideBuy, true
+	case "SELL", "SLD":
+		return models.SideSell, true
+	}
+	return "", false
+}
+
+func parseIBKRNumber(value string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", ""), 64)
+}
+
+// parseIBKRDate converts a Flex date in any of the formats a query can be set up with to YYYY-MM-DD
+func parseIBKRDate(value string) (string, bool) {
+	for _, layout := range ibkrDateLayouts {
+		if date, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return date.Format("2006-01-02"), true
+		}
+	}
+	return "", false
+}
+
+// parseIBKRTime reads the execution time from dateTime, or from tradeDate and
+// tradeTime when the query reports them separately. The separator between date
+// and time is configurable in Flex queries.
+func parseIBKRTime(trade ibkrTrade, loc *time.Location) (time.Time, error) {
+	value := strings.TrimSpace(trade.DateTime)
+	if value == "" {
+		value = strings.TrimSpace(trade.TradeDate + ";" + trade.TradeTime)
+	}
+
+	for _, dateLayout := range ibkrDateLayouts {
+		for _, timeLayout := range ibkrTimeLayouts {
+			for _, separator := range []string{";", ",", " ", ""} {
+				if at, err := time.ParseInLocation(dateLayout+separator+timeLayout, value, loc); err == nil {
+					return at, nil
+				}
+			}
+		}
+		// Trade-level rows without a time
+		if at, err := time.ParseInLocation(dateLayout, strings.TrimSuffix(value, ";"), loc); err == nil {
+			return at, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid dateTime %q", value)
+}
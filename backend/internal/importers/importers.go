@@ -0,0 +1,165 @@
+// Package importers parses broker export files into executions and trades.
+package importers
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/models"
+	"github.com/tradepulse/api/internal/positions"
+)
+
+var (
+	isoDatePattern = regexp.MustCompile(`(\d{4})-(\d{2})-(\d{2})`)
+	usDatePattern  = regexp.MustCompile(`(\d{1,2})[-_.](\d{1,2})[-_.](\d{4})`)
+)
+
+// TradeDateFromFilename extracts a trade date from names such as
+// "Ocean One 2025-10-03.csv" or "trades_10-03-2025.csv"
+func TradeDateFromFilename(name string, loc *time.Location) (time.Time, bool) {
+	if m := isoDatePattern.FindStringSubmatch(name); m != nil {
+		return buildDate(m[1], m[2], m[3], loc)
+	}
+	if m := usDatePattern.FindStringSubmatch(name); m != nil {
+		return buildDate(m[3], m[1], m[2], loc)
+	}
+	return time.Time{}, false
+}
+
+func buildDate(year, month, day string, loc *time.Location) (time.Time, bool) {
+	y, _ := strconv.Atoi(year)
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	if m < 1 || m > 12 || d < 1 || d > 31 {
+		return time.Time{}, false
+	}
+
+	date := time.Date(y, time.Month(m), d, 0, 0, 0, 0, loc)
+	if date.Day() != d {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// csvHeader maps the lower-cased column names of a CSV header row to their index
+type csvHeader map[string]int
+
+func newCSVHeader(record []string) csvHeader {
+	header := make(csvHeader)
+	for i, name := range record {
+		header[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	return header
+}
+
+// has reports whether any of the named columns is present
+func (h csvHeader) has(names ...string) bool {
+	for _, name := range names {
+		if _, ok := h[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// get returns the trimmed value of the first named column present in the header.
+// Exports from different versions of a platform name some columns differently.
+func (h csvHeader) get(record []string, names ...string) string {
+	for _, name := range names {
+		if i, ok := h[name]; ok {
+			if i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+	}
+	return ""
+}
+
+// optionSymbol builds the compact OCC symbol for an option contract, such as
+// "SPY250117C00600000": the root, expiry as YYMMDD, C or P and the strike in
+// thousandths. It is the form IBKR reports once its padding is removed.
+func optionSymbol(root string, expiry time.Time, right string, strike float64) string {
+	return fmt.Sprintf("%s%s%s%08d", strings.ToUpper(root), expiry.Format("060102"),
+		strings.ToUpper(right[:1]), int64(math.Round(strike*1000)))
+}
+
+// BuildTrades groups executions into round-trip trades. Open trades from earlier
+// imports are continued, so a position held overnight is closed by the next
+// file's fills; those the executions do not add to are left out of the result.
+func BuildTrades(executions []models.Execution, open []models.Trade) []models.Trade {
+	engine := positions.NewEngine()
+	engine.Seed(open)
+	engine.Process(executions)
+
+	seeded := make(map[uuid.UUID]int, len(open))
+	for _, trade := range open {
+		seeded[trade.ID] = len(trade.Executions)
+	}
+
+	trades := make([]models.Trade, 0)
+	for _, trade := range engine.Trades() {
+		if n, ok := seeded[trade.ID]; ok && trade.ID != uuid.Nil && len(trade.Executions) == n {
+			continue
+		}
+		trades = append(trades, trade)
+	}
+	return trades
+}
+
+// LinkOrders attaches each canceled or rejected order to the trade it belongs to:
+// the trade on the same account and symbol that was open when the order was
+// placed, or failing that the nearest trade in time. Orders on symbols with no
+// trades are returned unlinked.
+func LinkOrders(trades []models.Trade, orders []models.OrderEvent) []models.OrderEvent {
+	unlinked := make([]models.OrderEvent, 0)
+
+	for _, order := range orders {
+		best := -1
+		bestDistance := math.MaxFloat64
+
+		for i, trade := range trades {
+			if trade.Symbol != order.Symbol || tradeAccount(trade) != order.Account {
+				continue
+			}
+
+			distance := distanceToTrade(trade, order.EventAt)
+			if distance < bestDistance {
+				best = i
+				bestDistance = distance
+			}
+		}
+
+		if best < 0 {
+			unlinked = append(unlinked, order)
+			continue
+		}
+		trades[best].Orders = append(trades[best].Orders, order)
+	}
+
+	return unlinked
+}
+
+// distanceToTrade returns how far, in seconds, a moment lies outside a trade's
+// lifetime; zero while the trade is open
+func distanceToTrade(trade models.Trade, at time.Time) float64 {
+	if at.Before(trade.OpenedAt) {
+		return trade.OpenedAt.Sub(at).Seconds()
+	}
+	if trade.ClosedAt != nil && at.After(*trade.ClosedAt) {
+		return at.Sub(*trade.ClosedAt).Seconds()
+	}
+	return 0
+}
+
+func tradeAccount(trade models.Trade) string {
+	if len(trade.Executions) == 0 {
+		return ""
+	}
+	return trade.Executions[0].Account
+}
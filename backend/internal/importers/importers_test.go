@@ -0,0 +1,39 @@
+package importers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/models"
+)
+
+func TestBuildTradesContinuesOpenTrades(t *testing.T) {
+	opened := time.Date(2025, 11, 10, 15, 55, 0, 0, time.UTC)
+	fill := func(symbol string, side models.ExecutionSide, at time.Time) models.Execution {
+		return models.Execution{Account: "ACC1", Symbol: symbol, Side: side, Quantity: 100, Price: 10, ExecutedAt: at}
+	}
+
+	open := []models.Trade{
+		{ID: uuid.New(), Symbol: "AAPL", Executions: []models.Execution{fill("AAPL", models.SideBuy, opened)}},
+		{ID: uuid.New(), Symbol: "MSFT", Executions: []models.Execution{fill("MSFT", models.SideBuy, opened)}},
+	}
+
+	nextDay := opened.Add(18 * time.Hour)
+	trades := BuildTrades([]models.Execution{
+		fill("AAPL", models.SideSell, nextDay),
+		fill("TSLA", models.SideBuy, nextDay),
+	}, open)
+
+	// MSFT gained no fills, so it is left out rather than imported again
+	if len(trades) != 2 {
+		t.Fatalf("trades = %d, want the continued AAPL trade and a new TSLA one", len(trades))
+	}
+	if trades[0].ID != open[0].ID || !trades[0].Continued || trades[0].ClosedAt == nil {
+		t.Errorf("AAPL trade = %s, continued %v, closed %v; want the open trade continued and closed",
+			trades[0].ID, trades[0].Continued, trades[0].ClosedAt)
+	}
+	if trades[1].Symbol != "TSLA" || trades[1].Continued {
+		t.Errorf("second trade = %s, continued %v; want a new TSLA trade", trades[1].Symbol, trades[1].Continued)
+	}
+}
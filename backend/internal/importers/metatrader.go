@@ -0,0 +1,604 @@
+package importers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"golang.org/x/net/html"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// MetaTraderStatement is the result of parsing a MetaTrader 4 or 5 statement
+type MetaTraderStatement struct {
+	Platform   string // "MT4" or "MT5"
+	Account    string
+	Currency   string // account currency, such as "USD"
+	Executions []models.Execution
+	Cash       []CashActivity
+	Skipped    int // canceled pending orders, credit and other rows that are not trades
+}
+
+// metaTraderSection is a table of a statement that holds rows read here
+type metaTraderSection string
+
+const (
+	mtClosedPositions metaTraderSection = "closed"
+	mtOpenPositions   metaTraderSection = "open"
+	mtDeals           metaTraderSection = "deals"
+)
+
+// metaTraderSections maps the title row above each table to its section.
+// Titles that are not listed end the current section.
+var metaTraderSections = map[string]metaTraderSection{
+	"closed transactions": mtClosedPositions, // MT4
+	"open trades":         mtOpenPositions,
+	"positions":           mtClosedPositions, // MT5
+	"open positions":      mtOpenPositions,
+	"deals":               mtDeals,
+}
+
+// metaTraderContractSizes are the units in one lot of symbols whose contract
+// size is the same at most brokers. Currency pairs are 100,000 units of the
+// base currency; anything else, such as index CFDs, differs between brokers and
+// defaults to 1 unless the size is passed in.
+var metaTraderContractSizes = map[string]float64{
+	"XAUUSD": 100, "XAUEUR": 100, "XAGUSD": 5000, "XAGEUR": 5000,
+	"XPTUSD": 100, "XPDUSD": 100,
+}
+
+// fxCurrencies are the currency codes recognized in currency pair symbols
+var fxCurrencies = map[string]bool{
+	"USD": true, "EUR": true, "GBP": true, "JPY": true, "CHF": true, "AUD": true, "NZD": true,
+	"CAD": true, "SEK": true, "NOK": true, "DKK": true, "SGD": true, "HKD": true, "MXN": true,
+	"ZAR": true, "TRY": true, "PLN": true, "HUF": true, "CZK": true, "CNH": true,
+}
+
+var (
+	// "12345678 (USD, MetaQuotes-Demo, demo, Hedge)" on MT5, "12345678" on MT4
+	mtAccountPattern = regexp.MustCompile(`^(\S+?)(?:\s*\(([A-Z]{3})\b.*)?$`)
+	mtTimeLayouts    = []string{"2006.01.02 15:04:05", "2006.01.02 15:04", "2006-01-02 15:04:05"}
+	// Excel stores dates as days since 30 December 1899
+	excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+)
+
+// ParseMetaTraderStatement parses a MetaTrader 4 detailed statement or a
+// MetaTrader 5 trade history report, saved as HTML or, from MT5, as an Excel
+// workbook. Each closed position becomes an opening and a closing execution,
+// and open positions an opening one. Volumes are kept in lots with the contract
+// size as the multiplier; contractSizes overrides the size of a symbol.
+// Commission is charged on the opening execution and swap and taxes on the
+// closing one, which also carries MetaTrader's profit net of all three.
+// Positions in symbols not quoted in the account currency, such as USDJPY on a
+// USD account or an index CFD, are marked as foreign so their trades take that
+// profit as their P&L rather than converting price differences. Balance
+// operations become cash activity. Times are server times read in loc.
+func ParseMetaTraderStatement(r io.Reader, loc *time.Location, contractSizes map[string]float64) (*MetaTraderStatement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statement: %w", err)
+	}
+
+	var rows [][]string
+	if bytes.HasPrefix(data, xlsxSignature) {
+		rows, err = readXLSXRows(data)
+	} else {
+		rows, err = readHTMLRows(decodeMetaTraderText(data))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &MetaTraderStatement{
+		Executions: make([]models.Execution, 0),
+		Cash:       make([]CashActivity, 0),
+	}
+
+	var section metaTraderSection
+	var columns mtColumns
+	seenSection := false
+
+	for i, row := range rows {
+		cells := nonEmptyCells(row)
+		if len(cells) == 0 {
+			continue
+		}
+
+		if len(cells) == 1 {
+			title := strings.ToLower(strings.TrimSuffix(cells[0], ":"))
+			section = metaTraderSections[title]
+			columns = nil
+			if section == "" && !seenSection {
+				readMetaTraderAccount(row, statement)
+			}
+			if section != "" {
+				seenSection = true
+				if statement.Platform == "" {
+					statement.Platform = "MT5"
+					if strings.HasSuffix(cells[0], ":") {
+						statement.Platform = "MT4"
+					}
+				}
+			}
+			continue
+		}
+
+		if !seenSection {
+			readMetaTraderAccount(row, statement)
+			continue
+		}
+		if section == "" {
+			continue
+		}
+
+		if columns == nil {
+			if isMetaTraderHeader(row) {
+				columns = newMTColumns(row)
+			}
+			continue
+		}
+
+		var err error
+		switch section {
+		case mtClosedPositions, mtOpenPositions:
+			err = statement.addPosition(columns, row, section == mtOpenPositions, loc, contractSizes)
+		case mtDeals:
+			err = statement.addDeal(columns, row, loc)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+	}
+
+	if !seenSection {
+		return nil, fmt.Errorf("no positions or closed transactions table found: not a MetaTrader statement")
+	}
+
+	setCashAccount(statement.Cash, statement.Account)
+
+	return statement, nil
+}
+
+// addPosition reads a row of the MT4 closed transactions or open trades table,
+// or of the MT5 positions or open positions table
+func (s *MetaTraderStatement) addPosition(columns mtColumns, row []string, open bool, loc *time.Location, contractSizes map[string]float64) error {
+	kind := strings.ToLower(columns.get(row, "type", 0))
+	ticket := firstNonEmpty(columns.get(row, "position", 0), columns.get(row, "ticket", 0))
+
+	var side, closeSide models.ExecutionSide
+	switch kind {
+	case "buy":
+		side, closeSide = models.SideBuy, models.SideSell
+	case "sell":
+		side, closeSide = models.SideSell, models.SideBuy
+	case "balance":
+		// MT4 lists balance operations among the closed transactions, with
+		// the comment in the column after the type
+		return s.addCash(kind, columns.get(row, "profit", 0), cellAt(row, columns.index("type", 0)+1), ticket,
+			firstNonEmpty(columns.get(row, "open time", 0), columns.get(row, "time", 0)), loc)
+	case "":
+		return nil // totals
+	default:
+		s.Skipped++ // canceled pending orders and credit
+		return nil
+	}
+
+	symbol := strings.ToUpper(firstNonEmpty(columns.get(row, "symbol", 0), columns.get(row, "item", 0)))
+	if symbol == "" {
+		return fmt.Errorf("position %s has no symbol", ticket)
+	}
+
+	volume := firstNonEmpty(columns.get(row, "volume", 0), columns.get(row, "size", 0))
+	lots, err := parseMTNumber(volume)
+	if err != nil || lots <= 0 {
+		return fmt.Errorf("invalid volume %q", volume)
+	}
+
+	openPrice, err := parseMTNumber(columns.get(row, "price", 0))
+	if err != nil {
+		return fmt.Errorf("invalid open price %q", columns.get(row, "price", 0))
+	}
+
+	openedAt, err := parseMTTime(firstNonEmpty(columns.get(row, "open time", 0), columns.get(row, "time", 0)), loc)
+	if err != nil {
+		return err
+	}
+
+	commission, err := parseMTFee(columns.get(row, "commission", 0))
+	if err != nil {
+		return err
+	}
+
+	multiplier := metaTraderContractSize(symbol, contractSizes)
+	foreign := metaTraderQuoteCurrency(symbol) != s.Currency
+
+	s.Executions = append(s.Executions, models.Execution{
+		Account:      s.Account,
+		Symbol:       symbol,
+		Side:         side,
+		Quantity:     lots,
+		Price:        openPrice,
+		ExecutedAt:   openedAt,
+		OrderID:      ticket,
+		FillID:       ticket + "-in",
+		Commission:   debit(commission),
+		Multiplier:   multiplier,
+		ForeignQuote: foreign,
+	})
+	if open {
+		return nil
+	}
+
+	closePrice, err := parseMTNumber(columns.get(row, "price", 1))
+	if err != nil {
+		return fmt.Errorf("invalid close price %q", columns.get(row, "price", 1))
+	}
+
+	closedAt, err := parseMTTime(firstNonEmpty(columns.get(row, "close time", 0), columns.get(row, "time", 1)), loc)
+	if err != nil {
+		return err
+	}
+
+	swap, err := parseMTFee(columns.get(row, "swap", 0))
+	if err != nil {
+		return err
+	}
+	taxes, err := parseMTFee(columns.get(row, "taxes", 0))
+	if err != nil {
+		return err
+	}
+	profit, err := parseMTNumber(columns.get(row, "profit", 0))
+	if err != nil {
+		return fmt.Errorf("invalid profit %q", columns.get(row, "profit", 0))
+	}
+	pnl := profit + commission + swap + taxes
+
+	s.Executions = append(s.Executions, models.Execution{
+		Account:      s.Account,
+		Symbol:       symbol,
+		Side:         closeSide,
+		Quantity:     lots,
+		Price:        closePrice,
+		ExecutedAt:   closedAt,
+		OrderID:      ticket,
+		FillID:       ticket + "-out",
+		MiscFee:      debit(swap + taxes),
+		Multiplier:   multiplier,
+		RealizedPnL:  &pnl,
+		ForeignQuote: foreign,
+	})
+
+	return nil
+}
+
+// addDeal reads a row of the MT5 deals table. Trades are taken from the
+// positions table, which pairs the deals up, so only balance operations are
+// kept from here.
+func (s *MetaTraderStatement) addDeal(columns mtColumns, row []string, loc *time.Location) error {
+	kind := strings.ToLower(columns.get(row, "type", 0))
+	switch kind {
+	case "buy", "sell", "":
+		return nil
+	}
+	return s.addCash(kind, columns.get(row, "profit", 0), columns.get(row, "comment", 0),
+		columns.get(row, "deal", 0), columns.get(row, "time", 0), loc)
+}
+
+// addCash records a balance operation, classified by its MetaTrader type
+func (s *MetaTraderStatement) addCash(kind, amount, description, reference, at string, loc *time.Location) error {
+	value, err := parseMTNumber(amount)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q", amount)
+	}
+
+	var activityType CashActivityType
+	switch {
+	case kind == "balance":
+		activityType = fundingActivity(value)
+	case strings.Contains(kind, "commission"), strings.Contains(kind, "charge"), strings.Contains(kind, "fee"):
+		activityType = CashFee
+	case strings.Contains(kind, "interest"):
+		activityType = CashInterest
+	case strings.Contains(kind, "dividend"):
+		activityType = CashDividend
+	default:
+		s.Skipped++ // credit, bonus and corrections
+		return nil
+	}
+
+	occurredAt, err := parseMTTime(at, loc)
+	if err != nil {
+		return err
+	}
+
+	s.Cash = append(s.Cash, CashActivity{
+		Type:        activityType,
+		Amount:      value,
+		Description: description,
+		Reference:   reference,
+		OccurredAt:  occurredAt,
+	})
+	return nil
+}
+
+// readMetaTraderAccount picks the account number and currency out of the rows
+// above the first table: "Account: 123" and "Currency: USD" cells on MT4, and
+// an "Account:" cell followed by "123 (USD, Server, real, Hedge)" on MT5
+func readMetaTraderAccount(row []string, statement *MetaTraderStatement) {
+	for i, cell := range row {
+		label, value, ok := strings.Cut(cell, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			value = firstNonEmpty(row[i+1:]...)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(label)) {
+		case "account":
+			if m := mtAccountPattern.FindStringSubmatch(value); m != nil {
+				statement.Account = m[1]
+				if m[2] != "" {
+					statement.Currency = m[2]
+				}
+			}
+		case "currency":
+			statement.Currency = strings.ToUpper(value)
+		}
+	}
+}
+
+// mtColumns maps each lowercased column name of a table header to its
+// positions, since MT4 and MT5 repeat "Price" and MT5 "Time" for the open and
+// close of a position
+type mtColumns map[string][]int
+
+func newMTColumns(header []string) mtColumns {
+	columns := make(mtColumns)
+	for i, name := range header {
+		name = strings.ToLower(name)
+		if name != "" {
+			columns[name] = append(columns[name], i)
+		}
+	}
+	return columns
+}
+
+// index returns the position of the nth column with the name, or -1
+func (c mtColumns) index(name string, n int) int {
+	positions := c[name]
+	if n >= len(positions) {
+		return -1
+	}
+	return positions[n]
+}
+
+// get returns the value in the nth column with the name, or "" when the table has no such column
+func (c mtColumns) get(row []string, name string, n int) string {
+	return cellAt(row, c.index(name, n))
+}
+
+func cellAt(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return row[i]
+}
+
+func isMetaTraderHeader(row []string) bool {
+	columns := newMTColumns(row)
+	return columns.index("type", 0) >= 0 && (columns.index("time", 0) >= 0 || columns.index("open time", 0) >= 0)
+}
+
+// metaTraderContractSize returns the units in one lot of the symbol. Brokers
+// often add a suffix to their symbols, such as "EURUSD.m", so the table is
+// looked up by the first six letters.
+func metaTraderContractSize(symbol string, overrides map[string]float64) float64 {
+	if size, ok := overrides[symbol]; ok {
+		return size
+	}
+	if len(symbol) >= 6 {
+		if size, ok := overrides[symbol[:6]]; ok {
+			return size
+		}
+		if size, ok := metaTraderContractSizes[symbol[:6]]; ok {
+			return size
+		}
+		if fxCurrencies[symbol[:3]] && fxCurrencies[symbol[3:6]] {
+			return 100000
+		}
+	}
+	return 1
+}
+
+// metaTraderQuoteCurrency returns the currency a currency pair or metal is
+// quoted in, such as "JPY" for "USDJPY.m", or "" when the symbol is neither
+func metaTraderQuoteCurrency(symbol string) string {
+	if len(symbol) < 6 || !fxCurrencies[symbol[3:6]] {
+		return ""
+	}
+	if _, metal := metaTraderContractSizes[symbol[:6]]; metal || fxCurrencies[symbol[:3]] {
+		return symbol[3:6]
+	}
+	return ""
+}
+
+// ParseContractSizes reads contract sizes written as "US30=1, GER40=25"
+func ParseContractSizes(value string) (map[string]float64, error) {
+	sizes := make(map[string]float64)
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		symbol, size, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("contract size %q must be written as SYMBOL=SIZE", strings.TrimSpace(entry))
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(size), 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid contract size %q", strings.TrimSpace(entry))
+		}
+		sizes[strings.ToUpper(strings.TrimSpace(symbol))] = n
+	}
+	return sizes, nil
+}
+
+// decodeMetaTraderText returns a statement as UTF-8. MT5 saves its HTML
+// reports as UTF-16 with a byte order mark.
+func decodeMetaTraderText(data []byte) string {
+	var order binary.ByteOrder
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		order = binary.LittleEndian
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		order = binary.BigEndian
+	default:
+		return string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	}
+
+	data = data[2:]
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// readHTMLRows returns the text of the cells of every table row in a
+// document. A cell spanning several columns is followed by empty cells so
+// values line up with their header, and cells MT5 hides to pad its layout are
+// dropped.
+func readHTMLRows(document string) ([][]string, error) {
+	tokenizer := html.NewTokenizer(strings.NewReader(document))
+	rows := make([][]string, 0)
+
+	var row []string
+	var cell strings.Builder
+	inCell, hidden, span := false, false, 1
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF {
+				return nil, fmt.Errorf("failed to read HTML: %w", err)
+			}
+			if row != nil {
+				rows = append(rows, row)
+			}
+			return rows, nil
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "tr":
+				if row != nil {
+					rows = append(rows, row)
+				}
+				row = make([]string, 0)
+			case "td", "th":
+				inCell, hidden, span = true, false, 1
+				cell.Reset()
+				for _, attr := range token.Attr {
+					switch attr.Key {
+					case "colspan":
+						if n, err := strconv.Atoi(attr.Val); err == nil && n > 1 {
+							span = n
+						}
+					case "class":
+						hidden = strings.Contains(attr.Val, "hidden")
+					}
+				}
+			case "br":
+				cell.WriteString(" ")
+			}
+
+		case html.TextToken:
+			if inCell {
+				cell.Write(tokenizer.Text())
+			}
+
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "td", "th":
+				if inCell && !hidden && row != nil {
+					row = append(row, strings.Join(strings.Fields(cell.String()), " "))
+					for i := 1; i < span; i++ {
+						row = append(row, "")
+					}
+				}
+				inCell = false
+			case "tr":
+				if row != nil {
+					rows = append(rows, row)
+				}
+				row = nil
+			}
+		}
+	}
+}
+
+func nonEmptyCells(row []string) []string {
+	cells := make([]string, 0)
+	for _, cell := range row {
+		if cell = strings.TrimSpace(cell); cell != "" {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// parseMTNumber reads a MetaTrader number, which uses spaces to group
+// thousands. MT5 writes volumes as "filled / requested".
+func parseMTNumber(value string) (float64, error) {
+	value, _, _ = strings.Cut(value, "/")
+	return parseAmount(strings.ReplaceAll(value, "\u00a0", ""))
+}
+
+// parseMTFee reads an optional fee column, where an empty cell means no charge
+func parseMTFee(value string) (float64, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+	fee, err := parseMTNumber(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid fee %q", value)
+	}
+	return fee, nil
+}
+
+// parseMTTime reads a MetaTrader server time, or an Excel serial date from an
+// MT5 workbook, in loc
+func parseMTTime(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range mtTimeLayouts {
+		if at, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return at, nil
+		}
+	}
+
+	if days, err := strconv.ParseFloat(value, 64); err == nil && days > 0 {
+		at := excelEpoch.Add(time.Duration(math.Round(days*86400)) * time.Second)
+		return time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute(), at.Second(), 0, loc), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
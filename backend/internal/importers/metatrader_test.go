@@ -0,0 +1,57 @@
+package importers
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMetaTraderStatementGolden(t *testing.T) {
+	for _, name := range []string{"mt4_statement.htm", "mt5_report.html"} {
+		t.Run(name, func(t *testing.T) {
+			statement, err := ParseMetaTraderStatement(openFixture(t, "metatrader", name), time.UTC, map[string]float64{"US30": 1})
+			if err != nil {
+				t.Fatalf("ParseMetaTraderStatement: %v", err)
+			}
+
+			assertGolden(t, "metatrader/"+strings.TrimSuffix(name, filepath.Ext(name)), statement)
+		})
+	}
+}
+
+func TestMetaTraderQuoteCurrency(t *testing.T) {
+	tests := map[string]string{
+		"EURUSD":   "USD",
+		"USDJPY.m": "JPY",
+		"XAUUSD":   "USD",
+		"XAGEUR":   "EUR",
+		"US30":     "",
+		"GER40EUR": "",
+		"BTCUSD":   "",
+	}
+	for symbol, want := range tests {
+		if got := metaTraderQuoteCurrency(symbol); got != want {
+			t.Errorf("metaTraderQuoteCurrency(%q) = %q, want %q", symbol, got, want)
+		}
+	}
+}
+
+func TestParseMTTime(t *testing.T) {
+	tests := map[string]time.Time{
+		"2025.11.10 09:15:02": time.Date(2025, 11, 10, 9, 15, 2, 0, time.UTC),
+		"2025.11.10 09:15":    time.Date(2025, 11, 10, 9, 15, 0, 0, time.UTC),
+		// An Excel serial date from an MT5 workbook
+		"45971.385439814815": time.Date(2025, 11, 10, 9, 15, 2, 0, time.UTC),
+	}
+	for value, want := range tests {
+		got, err := parseMTTime(value, time.UTC)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseMTTime(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+
+	if _, err := parseMTTime("10/11/2025", time.UTC); err == nil {
+		t.Error("parseMTTime(\"10/11/2025\") succeeded, want an error")
+	}
+}
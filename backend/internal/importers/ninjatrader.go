@@ -0,0 +1,112 @@
+package importers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// ninjaTraderColumns are the columns every NinjaTrader executions export must have
+var ninjaTraderColumns = []string{"instrument", "action", "quantity", "price", "time"}
+
+// ParseNinjaTraderExecutions parses the CSV export of NinjaTrader's Executions
+// grid. Instruments such as "ES 12-25" are converted to their exchange code and
+// point value; commissions are per execution. Times are read in loc.
+func ParseNinjaTraderExecutions(r io.Reader, loc *time.Location) ([]models.Execution, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.FieldsPerRecord = -1 // Rows end with a trailing comma
+	csvReader.TrimLeadingSpace = true
+
+	record, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	header := newCSVHeader(record)
+	for _, name := range ninjaTraderColumns {
+		if !header.has(name) {
+			return nil, fmt.Errorf("missing column %q: not a NinjaTrader executions export", name)
+		}
+	}
+
+	executions := make([]models.Execution, 0)
+	line := 1
+
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		instrument := header.get(record, "instrument")
+		if instrument == "" {
+			continue
+		}
+
+		side, ok := parseNinjaTraderAction(header.get(record, "action"))
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown action %q", line, header.get(record, "action"))
+		}
+
+		quantity, err := parseAmount(header.get(record, "quantity"))
+		if err != nil || quantity <= 0 {
+			return nil, fmt.Errorf("line %d: invalid quantity %q", line, header.get(record, "quantity"))
+		}
+
+		price, err := parseAmount(header.get(record, "price"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid price %q", line, header.get(record, "price"))
+		}
+
+		at, err := parseFuturesTime(header.get(record, "time"), loc)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		symbol, multiplier, err := futuresInstrument(instrument, at)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		var commission float64
+		if value := header.get(record, "commission"); value != "" {
+			commission, err = parseAmount(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid commission %q", line, value)
+			}
+		}
+
+		executions = append(executions, models.Execution{
+			Account:    header.get(record, "account"),
+			Symbol:     symbol,
+			Side:       side,
+			Quantity:   quantity,
+			Price:      price,
+			ExecutedAt: at,
+			OrderID:    header.get(record, "order id"),
+			FillID:     header.get(record, "id"),
+			Commission: commission,
+			Multiplier: multiplier,
+		})
+	}
+
+	return executions, nil
+}
+
+func parseNinjaTraderAction(value string) (models.ExecutionSide, bool) {
+	switch strings.ToLower(strings.ReplaceAll(value, " ", "")) {
+	case "buy", "buytocover":
+		return models.SideBuy, true
+	case "sell", "sellshort":
+		return models.SideSell, true
+	}
+	return "", false
+}
@@ -0,0 +1,15 @@
+package importers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseNinjaTraderExecutionsGolden(t *testing.T) {
+	executions, err := ParseNinjaTraderExecutions(openFixture(t, "ninjatrader", "executions.csv"), time.UTC)
+	if err != nil {
+		t.Fatalf("ParseNinjaTraderExecutions: %v", err)
+	}
+
+	assertGolden(t, "ninjatrader/executions", executions)
+}
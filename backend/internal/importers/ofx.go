@@ -0,0 +1,506 @@
+package importers
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// OFXStatement is the result of parsing an OFX or QFX investment statement download
+type OFXStatement struct {
+	Accounts   []string
+	Currency   string // default currency of the statement, such as "USD"
+	Executions []models.Execution
+	Cash       []CashActivity
+	Skipped    int // transfers, reinvestments, splits and other transactions that are neither trades nor cash
+}
+
+// ofxNode is an element of an OFX document. Aggregates have children; data
+// elements have a value.
+type ofxNode struct {
+	Name     string
+	Value    string
+	Children []*ofxNode
+}
+
+// child returns the first direct child with the name, or nil
+func (n *ofxNode) child(name string) *ofxNode {
+	if n == nil {
+		return nil
+	}
+	for _, c := range n.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// value returns the value at a path of child names, or "" when any is missing
+func (n *ofxNode) value(path ...string) string {
+	for _, name := range path {
+		n = n.child(name)
+	}
+	if n == nil {
+		return ""
+	}
+	return n.Value
+}
+
+// findAll returns every element with the name, at any depth, in document order
+func (n *ofxNode) findAll(name string) []*ofxNode {
+	found := make([]*ofxNode, 0)
+	for _, c := range n.Children {
+		if c.Name == name {
+			found = append(found, c)
+		}
+		found = append(found, c.findAll(name)...)
+	}
+	return found
+}
+
+// ofxSecurity is an entry of the security list that transactions refer to by ID
+type ofxSecurity struct {
+	ticker     string
+	name       string
+	option     bool
+	right      string // CALL or PUT
+	strike     float64
+	expiry     time.Time
+	underlying string // security ID of an option's underlying
+	multiplier float64
+}
+
+// ParseOFX parses an OFX 1.x (SGML) or 2.x (XML) investment statement, which
+// is also what Quicken's QFX files contain. Stock, option, fund and other buys
+// and sells become executions, with their commission and fees; securities are
+// named by the ticker in the statement's security list. Income, bank
+// transactions, expenses and margin interest become cash activity. Times that
+// carry no zone are read in loc.
+func ParseOFX(r io.Reader, loc *time.Location) (*OFXStatement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	root, err := parseOFXDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := root.findAll("INVSTMTRS")
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("file has no investment statement (INVSTMTRS)")
+	}
+
+	securities, err := parseOFXSecurities(root, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OFXStatement{
+		Accounts:   make([]string, 0),
+		Executions: make([]models.Execution, 0),
+		Cash:       make([]CashActivity, 0),
+	}
+
+	for _, statement := range statements {
+		account := statement.value("INVACCTFROM", "ACCTID")
+		if account != "" {
+			result.Accounts = append(result.Accounts, account)
+		}
+		if result.Currency == "" {
+			result.Currency = strings.ToUpper(statement.value("CURDEF"))
+		}
+
+		transactions := statement.child("INVTRANLIST")
+		if transactions == nil {
+			continue
+		}
+
+		first := len(result.Cash)
+		for _, transaction := range transactions.Children {
+			if err := result.addTransaction(transaction, account, securities, loc); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", transaction.Name, transaction.value("INVTRAN", "FITID"), err)
+			}
+		}
+		setCashAccount(result.Cash[first:], account)
+	}
+
+	return result, nil
+}
+
+func (s *OFXStatement) addTransaction(transaction *ofxNode, account string, securities map[string]ofxSecurity, loc *time.Location) error {
+	switch transaction.Name {
+	case "DTSTART", "DTEND":
+		return nil
+	case "BUYSTOCK", "BUYOPT", "BUYMF", "BUYDEBT", "BUYOTHER":
+		return s.addTrade(transaction, transaction.child("INVBUY"), models.SideBuy, account, securities, loc)
+	case "SELLSTOCK", "SELLOPT", "SELLMF", "SELLDEBT", "SELLOTHER":
+		return s.addTrade(transaction, transaction.child("INVSELL"), models.SideSell, account, securities, loc)
+	case "INCOME":
+		return s.addIncome(transaction, securities, loc)
+	case "INVBANKTRAN":
+		return s.addBankTransaction(transaction.child("STMTTRN"), loc)
+	case "INVEXPENSE":
+		return s.addCharge(transaction, CashFee, loc)
+	case "MARGININTEREST":
+		return s.addCharge(transaction, CashInterest, loc)
+	}
+	s.Skipped++
+	return nil
+}
+
+// addTrade reads the INVBUY or INVSELL aggregate of a buy or sell transaction
+func (s *OFXStatement) addTrade(transaction, detail *ofxNode, side models.ExecutionSide, account string, securities map[string]ofxSecurity, loc *time.Location) error {
+	if detail == nil {
+		return fmt.Errorf("missing INVBUY or INVSELL")
+	}
+
+	at, err := parseOFXTime(detail.value("INVTRAN", "DTTRADE"), loc)
+	if err != nil {
+		return err
+	}
+
+	units, err := parseOFXNumber(detail.value("UNITS"), "UNITS")
+	if err != nil {
+		return err
+	}
+	if units == 0 {
+		return fmt.Errorf("invalid UNITS %q", detail.value("UNITS"))
+	}
+	price, err := parseOFXNumber(detail.value("UNITPRICE"), "UNITPRICE")
+	if err != nil {
+		return err
+	}
+
+	var fees [4]float64
+	for i, name := range []string{"COMMISSION", "FEES", "TAXES", "LOAD"} {
+		if fees[i], err = parseOFXNumber(detail.value(name), name); err != nil {
+			return err
+		}
+	}
+
+	symbol, multiplier := ofxInstrument(detail.child("SECID"), securities)
+	if contracts := transaction.value("SHPERCTRCT"); contracts != "" {
+		if multiplier, err = parseOFXNumber(contracts, "SHPERCTRCT"); err != nil {
+			return err
+		}
+	}
+	if symbol == "" {
+		return fmt.Errorf("missing SECID")
+	}
+
+	s.Executions = append(s.Executions, models.Execution{
+		Account:    account,
+		Symbol:     symbol,
+		Side:       side,
+		Quantity:   math.Abs(units),
+		Price:      price,
+		ExecutedAt: at,
+		FillID:     detail.value("INVTRAN", "FITID"),
+		Commission: fees[0],
+		MiscFee:    fees[1] + fees[2] + fees[3],
+		Multiplier: multiplier,
+	})
+	return nil
+}
+
+// addIncome records dividends, interest and capital gain distributions
+func (s *OFXStatement) addIncome(transaction *ofxNode, securities map[string]ofxSecurity, loc *time.Location) error {
+	var activityType CashActivityType
+	switch strings.ToUpper(transaction.value("INCOMETYPE")) {
+	case "DIV", "CGLONG", "CGSHORT":
+		activityType = CashDividend
+	case "INTEREST":
+		activityType = CashInterest
+	default:
+		activityType = CashOther
+	}
+
+	amount, err := parseOFXNumber(transaction.value("TOTAL"), "TOTAL")
+	if err != nil {
+		return err
+	}
+	at, err := parseOFXTime(transaction.value("INVTRAN", "DTTRADE"), loc)
+	if err != nil {
+		return err
+	}
+
+	description := transaction.value("INVTRAN", "MEMO")
+	if description == "" {
+		if security, ok := securities[ofxSecurityID(transaction.child("SECID"))]; ok {
+			description = firstNonEmpty(security.ticker, security.name)
+		}
+	}
+
+	s.Cash = append(s.Cash, CashActivity{
+		Type:        activityType,
+		Amount:      amount,
+		Description: description,
+		Reference:   transaction.value("INVTRAN", "FITID"),
+		OccurredAt:  at,
+	})
+	return nil
+}
+
+// addBankTransaction records a movement of cash in the account, classified by its TRNTYPE
+func (s *OFXStatement) addBankTransaction(transaction *ofxNode, loc *time.Location) error {
+	if transaction == nil {
+		return fmt.Errorf("missing STMTTRN")
+	}
+
+	amount, err := parseOFXNumber(transaction.value("TRNAMT"), "TRNAMT")
+	if err != nil {
+		return err
+	}
+	at, err := parseOFXTime(transaction.value("DTPOSTED"), loc)
+	if err != nil {
+		return err
+	}
+
+	var activityType CashActivityType
+	switch strings.ToUpper(transaction.value("TRNTYPE")) {
+	case "INT":
+		activityType = CashInterest
+	case "DIV":
+		activityType = CashDividend
+	case "FEE", "SRVCHG":
+		activityType = CashFee
+	default:
+		activityType = fundingActivity(amount)
+	}
+
+	s.Cash = append(s.Cash, CashActivity{
+		Type:        activityType,
+		Amount:      amount,
+		Description: firstNonEmpty(transaction.value("MEMO"), transaction.value("NAME")),
+		Reference:   transaction.value("FITID"),
+		OccurredAt:  at,
+	})
+	return nil
+}
+
+// addCharge records an investment expense or margin interest, which take cash out of the account
+func (s *OFXStatement) addCharge(transaction *ofxNode, activityType CashActivityType, loc *time.Location) error {
+	amount, err := parseOFXNumber(transaction.value("TOTAL"), "TOTAL")
+	if err != nil {
+		return err
+	}
+	at, err := parseOFXTime(transaction.value("INVTRAN", "DTTRADE"), loc)
+	if err != nil {
+		return err
+	}
+
+	s.Cash = append(s.Cash, CashActivity{
+		Type:        activityType,
+		Amount:      -math.Abs(amount),
+		Description: transaction.value("INVTRAN", "MEMO"),
+		Reference:   transaction.value("INVTRAN", "FITID"),
+		OccurredAt:  at,
+	})
+	return nil
+}
+
+// parseOFXSecurities indexes the security list by security ID
+func parseOFXSecurities(root *ofxNode, loc *time.Location) (map[string]ofxSecurity, error) {
+	securities := make(map[string]ofxSecurity)
+
+	for _, list := range root.findAll("SECLIST") {
+		for _, entry := range list.Children {
+			info := entry.child("SECINFO")
+			if info == nil {
+				continue
+			}
+
+			security := ofxSecurity{
+				ticker:     strings.ToUpper(strings.Join(strings.Fields(info.value("TICKER")), "")),
+				name:       info.value("SECNAME"),
+				multiplier: 1,
+			}
+
+			if entry.Name == "OPTINFO" {
+				security.option = true
+				security.right = strings.ToUpper(entry.value("OPTTYPE"))
+				security.underlying = ofxSecurityID(entry.child("SECID"))
+				security.multiplier = 100
+
+				var err error
+				if value := entry.value("STRIKEPRICE"); value != "" {
+					if security.strike, err = parseOFXNumber(value, "STRIKEPRICE"); err != nil {
+						return nil, err
+					}
+				}
+				if value := entry.value("DTEXPIRE"); value != "" {
+					if security.expiry, err = parseOFXTime(value, loc); err != nil {
+						return nil, err
+					}
+				}
+				if value := entry.value("SHPERCTRCT"); value != "" {
+					if security.multiplier, err = parseOFXNumber(value, "SHPERCTRCT"); err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			securities[ofxSecurityID(info.child("SECID"))] = security
+		}
+	}
+
+	return securities, nil
+}
+
+// ofxInstrument returns the symbol and multiplier of the security a
+// transaction refers to. Options are given their OCC symbol when the security
+// list describes the contract; securities missing from the list keep their ID.
+func ofxInstrument(secID *ofxNode, securities map[string]ofxSecurity) (string, float64) {
+	id := ofxSecurityID(secID)
+	security, ok := securities[id]
+	if !ok {
+		return secID.value("UNIQUEID"), 1
+	}
+
+	if security.option && security.right != "" && !security.expiry.IsZero() {
+		if underlying, ok := securities[security.underlying]; ok && underlying.ticker != "" {
+			return optionSymbol(underlying.ticker, security.expiry, security.right, security.strike), security.multiplier
+		}
+	}
+
+	if security.ticker == "" {
+		return secID.value("UNIQUEID"), security.multiplier
+	}
+	return security.ticker, security.multiplier
+}
+
+// ofxSecurityID identifies a security by its ID type and ID, such as "CUSIP:037833100"
+func ofxSecurityID(secID *ofxNode) string {
+	return strings.ToUpper(secID.value("UNIQUEIDTYPE")) + ":" + secID.value("UNIQUEID")
+}
+
+func parseOFXNumber(value, name string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	// OFX 1.x allows a comma as the decimal separator
+	n, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return n, nil
+}
+
+// parseOFXTime reads an OFX date and time, "YYYYMMDD[HHMMSS[.XXX]][[offset:TZ]]",
+// such as "20240115093000.000[-5:EST]". Times without an offset are read in loc.
+func parseOFXTime(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	stamp, zone, hasZone := strings.Cut(value, "[")
+	stamp, _, _ = strings.Cut(stamp, ".")
+
+	if hasZone {
+		offsetText, _, _ := strings.Cut(strings.TrimSuffix(zone, "]"), ":")
+		offset, err := strconv.ParseFloat(offsetText, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time zone in %q", value)
+		}
+		loc = time.FixedZone("", int(offset*3600))
+	}
+
+	for _, layout := range []string{"20060102150405", "200601021504", "20060102"} {
+		if len(stamp) == len(layout) {
+			if at, err := time.ParseInLocation(layout, stamp, loc); err == nil {
+				return at, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// parseOFXDocument reads the <OFX> element of a file into a tree. OFX 1.x is
+// SGML, where data elements are not closed: an element followed by text is
+// taken as a data element and an element followed by another tag as an
+// aggregate. Closing tags, which OFX 2.x always writes, end the innermost open
+// aggregate with their name.
+func parseOFXDocument(data []byte) (*ofxNode, error) {
+	start := bytes.Index(bytes.ToUpper(data), []byte("<OFX>"))
+	if start < 0 {
+		return nil, fmt.Errorf("no <OFX> element found: not an OFX or QFX file")
+	}
+	document := string(data[start:])
+
+	root := &ofxNode{}
+	stack := []*ofxNode{root}
+
+	for len(document) > 0 {
+		open := strings.IndexByte(document, '<')
+		if open < 0 {
+			break
+		}
+		document = document[open:]
+
+		if strings.HasPrefix(document, "<!--") || strings.HasPrefix(document, "<?") {
+			end := strings.IndexByte(document, '>')
+			if end < 0 {
+				break
+			}
+			document = document[end+1:]
+			continue
+		}
+
+		end := strings.IndexByte(document, '>')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated tag")
+		}
+		tag := strings.TrimSpace(document[1:end])
+		document = document[end+1:]
+
+		if name, closing := strings.CutPrefix(tag, "/"); closing {
+			name = strings.ToUpper(strings.TrimSpace(name))
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].Name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+			continue
+		}
+
+		if tag == "" {
+			continue
+		}
+		node := &ofxNode{Name: strings.ToUpper(strings.TrimSuffix(strings.Fields(tag)[0], "/"))}
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, node)
+
+		text := document
+		if next := strings.IndexByte(document, '<'); next >= 0 {
+			text = document[:next]
+		}
+		if value := strings.TrimSpace(text); value != "" {
+			node.Value = html.UnescapeString(value)
+			document = document[len(text):]
+			// An OFX 2.x data element is closed straight after its value
+			if closing := "</" + node.Name + ">"; len(document) >= len(closing) && strings.EqualFold(document[:len(closing)], closing) {
+				document = document[len(closing):]
+			}
+			continue
+		}
+
+		if strings.HasSuffix(tag, "/") {
+			continue // empty XML element
+		}
+		stack = append(stack, node)
+	}
+
+	ofx := root.child("OFX")
+	if ofx == nil {
+		return nil, fmt.Errorf("no <OFX> element found: not an OFX or QFX file")
+	}
+	return ofx, nil
+}
@@ -0,0 +1,44 @@
+package importers
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseOFXGolden(t *testing.T) {
+	// OFX 1.x SGML and a Quicken QFX download of OFX 2.x XML
+	for _, name := range []string{"sgml_statement.ofx", "xml_statement.qfx"} {
+		t.Run(name, func(t *testing.T) {
+			statement, err := ParseOFX(openFixture(t, "ofx", name), time.UTC)
+			if err != nil {
+				t.Fatalf("ParseOFX: %v", err)
+			}
+
+			assertGolden(t, "ofx/"+strings.TrimSuffix(name, filepath.Ext(name)), statement)
+		})
+	}
+}
+
+func TestParseOFXTime(t *testing.T) {
+	tests := map[string]time.Time{
+		"20251110":                   time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC),
+		"202511100935":               time.Date(2025, 11, 10, 9, 35, 0, 0, time.UTC),
+		"20251110093512.000[-5:EST]": time.Date(2025, 11, 10, 14, 35, 12, 0, time.UTC),
+		"20251110093512[+5.5:IST]":   time.Date(2025, 11, 10, 4, 5, 12, 0, time.UTC),
+		"20251110093512.123[0:GMT]":  time.Date(2025, 11, 10, 9, 35, 12, 0, time.UTC),
+	}
+	for value, want := range tests {
+		got, err := parseOFXTime(value, time.UTC)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseOFXTime(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{"2025-11-10", "20251110[EST]"} {
+		if _, err := parseOFXTime(value, time.UTC); err == nil {
+			t.Errorf("parseOFXTime(%q) succeeded, want an error", value)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package importers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// defaultSides is the side vocabulary understood by every profile
+var defaultSides = map[string]models.ExecutionSide{
+	"B":      models.SideBuy,
+	"BUY":    models.SideBuy,
+	"BOT":    models.SideBuy,
+	"BOUGHT": models.SideBuy,
+	"BTO":    models.SideBuy,
+	"BTC":    models.SideBuy,
+	"S":      models.SideSell,
+	"SELL":   models.SideSell,
+	"SLD":    models.SideSell,
+	"SOLD":   models.SideSell,
+	"SS":     models.SideSell,
+	"SHORT":  models.SideSell,
+	"STO":    models.SideSell,
+	"STC":    models.SideSell,
+}
+
+// ValidateProfile checks that a profile maps enough columns to build executions
+func ValidateProfile(profile models.ImportProfile) error {
+	if strings.TrimSpace(profile.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	for _, field := range []string{models.ImportFieldSymbol, models.ImportFieldSide, models.ImportFieldQuantity, models.ImportFieldPrice} {
+		if profile.ColumnMapping[field] == "" {
+			return fmt.Errorf("column mapping for %q is required", field)
+		}
+	}
+
+	if profile.ColumnMapping[models.ImportFieldDate] == "" && profile.ColumnMapping[models.ImportFieldTime] == "" {
+		return fmt.Errorf("column mapping for %q or %q is required", models.ImportFieldDate, models.ImportFieldTime)
+	}
+	if profile.ColumnMapping[models.ImportFieldDate] != "" && profile.DateFormat == "" {
+		return fmt.Errorf("date_format is required when a date column is mapped")
+	}
+	if profile.ColumnMapping[models.ImportFieldTime] != "" && profile.TimeFormat == "" {
+		return fmt.Errorf("time_format is required when a time column is mapped")
+	}
+
+	if _, err := time.LoadLocation(profile.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", profile.Timezone)
+	}
+	if _, err := profileDelimiter(profile); err != nil {
+		return err
+	}
+
+	for value, side := range profile.SideMapping {
+		if side != models.SideBuy && side != models.SideSell {
+			return fmt.Errorf("side mapping for %q must be BUY or SELL", value)
+		}
+	}
+
+	return nil
+}
+
+// ParseWithProfile reads a broker CSV using a saved import profile. When the
+// profile maps no date column, tradeDate supplies the date for every row.
+func ParseWithProfile(r io.Reader, profile models.ImportProfile, tradeDate time.Time) ([]models.Execution, error) {
+	if err := ValidateProfile(profile); err != nil {
+		return nil, err
+	}
+
+	loc, _ := time.LoadLocation(profile.Timezone)
+	delimiter, _ := profileDelimiter(profile)
+
+	if profile.ColumnMapping[models.ImportFieldDate] == "" && tradeDate.IsZero() {
+		return nil, fmt.Errorf("a trade date is required because the profile has no date column")
+	}
+
+	csvReader := csv.NewReader(r)
+	csvReader.Comma = delimiter
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+	csvReader.LazyQuotes = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	headerIndex := make(map[string]int)
+	for i, name := range header {
+		headerIndex[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	columns := make(map[string]int)
+	for field, column := range profile.ColumnMapping {
+		if column == "" {
+			continue
+		}
+		i, ok := headerIndex[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			return nil, fmt.Errorf("column %q mapped to %q not found in file", column, field)
+		}
+		columns[field] = i
+	}
+
+	value := func(record []string, field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	number := func(record []string, field string) (float64, error) {
+		raw := value(record, field)
+		if raw == "" {
+			return 0, nil
+		}
+		return parseAmount(raw)
+	}
+
+	executions := make([]models.Execution, 0)
+	line := 1
+
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		// Skip blank lines and trailing summary rows without a symbol
+		symbol := strings.ToUpper(value(record, models.ImportFieldSymbol))
+		if symbol == "" {
+			continue
+		}
+
+		rawSide := value(record, models.ImportFieldSide)
+		side, ok := lookupSide(profile.SideMapping, rawSide)
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown side %q", line, rawSide)
+		}
+
+		quantity, err := number(record, models.ImportFieldQuantity)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid quantity: %w", line, err)
+		}
+		// Some brokers sign the quantity instead of using a side column
+		if quantity < 0 {
+			quantity = -quantity
+		}
+
+		price, err := number(record, models.ImportFieldPrice)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid price: %w", line, err)
+		}
+
+		executedAt, err := profileTimestamp(profile, value(record, models.ImportFieldDate), value(record, models.ImportFieldTime), tradeDate, loc)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		execution := models.Execution{
+			Account:    value(record, models.ImportFieldAccount),
+			Symbol:     symbol,
+			Side:       side,
+			Quantity:   quantity,
+			Price:      price,
+			ExecutedAt: executedAt,
+			Route:      value(record, models.ImportFieldRoute),
+			Liquidity:  value(record, models.ImportFieldLiquidity),
+			OrderID:    value(record, models.ImportFieldOrderID),
+			FillID:     value(record, models.ImportFieldFillID),
+		}
+
+		fees := []struct {
+			field  string
+			target *float64
+		}{
+			{models.ImportFieldCommission, &execution.Commission},
+			{models.ImportFieldECNFee, &execution.ECNFee},
+			{models.ImportFieldSECFee, &execution.SECFee},
+			{models.ImportFieldTAFFee, &execution.TAFFee},
+			{models.ImportFieldNSCCFee, &execution.NSCCFee},
+			{models.ImportFieldClearingFee, &execution.ClearingFee},
+			{models.ImportFieldMiscFee, &execution.MiscFee},
+		}
+		for _, fee := range fees {
+			amount, err := number(record, fee.field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s: %w", line, fee.field, err)
+			}
+			// A rebate is kept as a negative fee whichever way the broker signs fees
+			if profile.FeesAsDebits {
+				amount = debit(amount)
+			}
+			*fee.target = amount
+		}
+
+		executions = append(executions, execution)
+	}
+
+	return executions, nil
+}
+
+func profileTimestamp(profile models.ImportProfile, date, clock string, tradeDate time.Time, loc *time.Location) (time.Time, error) {
+	if date != "" && clock != "" {
+		t, err := time.ParseInLocation(profile.DateFormat+" "+profile.TimeFormat, date+" "+clock, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date/time %q %q", date, clock)
+		}
+		return t, nil
+	}
+
+	if date != "" {
+		t, err := time.ParseInLocation(profile.DateFormat, date, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q", date)
+		}
+		return t, nil
+	}
+
+	if clock == "" || tradeDate.IsZero() {
+		return time.Time{}, fmt.Errorf("missing date and time")
+	}
+
+	t, err := time.Parse(profile.TimeFormat, clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", clock)
+	}
+	return time.Date(tradeDate.Year(), tradeDate.Month(), tradeDate.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc), nil
+}
+
+func lookupSide(mapping map[string]models.ExecutionSide, value string) (models.ExecutionSide, bool) {
+	key := strings.ToUpper(strings.TrimSpace(value))
+	for k, side := range mapping {
+		if strings.ToUpper(k) == key {
+			return side, true
+		}
+	}
+	side, ok := defaultSides[key]
+	return side, ok
+}
+
+func profileDelimiter(profile models.ImportProfile) (rune, error) {
+	switch profile.Delimiter {
+	case "", ",":
+		return ',', nil
+	case "\\t", "\t", "tab":
+		return '\t', nil
+	}
+
+	if utf8.RuneCountInString(profile.Delimiter) != 1 {
+		return 0, fmt.Errorf("delimiter must be a single character")
+	}
+	r, _ := utf8.DecodeRuneInString(profile.Delimiter)
+	return r, nil
+}
+
+// parseAmount parses numbers as brokers write them: "1,234.50", "$12.00", "(3.25)", "$(3.25)"
+func parseAmount(raw string) (float64, error) {
+	s := strings.ReplaceAll(strings.TrimSpace(raw), "$", "")
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = s[1 : len(s)-1]
+	}
+	s = strings.NewReplacer(",", "", " ", "").Replace(s)
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", raw)
+	}
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// debit converts a charge reported as a negative amount into a positive fee.
+// A positive amount, such as a rebate, becomes a negative fee.
+func debit(amount float64) float64 {
+	if amount == 0 {
+		return 0
+	}
+	return -amount
+}
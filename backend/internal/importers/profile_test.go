@@ -0,0 +1,60 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+func TestParseWithProfileFeeSigns(t *testing.T) {
+	profile := models.ImportProfile{
+		Name: "Broker",
+		ColumnMapping: map[string]string{
+			models.ImportFieldSymbol:     "Symbol",
+			models.ImportFieldSide:       "Side",
+			models.ImportFieldQuantity:   "Qty",
+			models.ImportFieldPrice:      "Price",
+			models.ImportFieldDate:       "Date",
+			models.ImportFieldCommission: "Comm",
+			models.ImportFieldECNFee:     "ECN",
+		},
+		DateFormat: "2006-01-02 15:04:05",
+		Timezone:   "UTC",
+	}
+
+	tests := []struct {
+		name       string
+		debits     bool
+		row        string
+		commission float64
+		ecn        float64
+	}{
+		// Adding liquidity earns a rebate, written with the opposite sign to a charge
+		{"charges positive", false, "1.00,-0.30", 1, -0.3},
+		{"charges positive, parenthesized rebate", false, "1.00,(0.30)", 1, -0.3},
+		{"charges as debits", true, "-1.00,0.30", 1, -0.3},
+		{"no fees", true, "0,", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile.FeesAsDebits = tt.debits
+			csv := "Symbol,Side,Qty,Price,Date,Comm,ECN\n" +
+				"AAPL,B,100,230.10,2025-11-10 14:35:00," + tt.row + "\n"
+
+			executions, err := ParseWithProfile(strings.NewReader(csv), profile, time.Time{})
+			if err != nil {
+				t.Fatalf("ParseWithProfile: %v", err)
+			}
+			if len(executions) != 1 {
+				t.Fatalf("executions = %d, want 1", len(executions))
+			}
+			e := executions[0]
+			if !approx(e.Commission, tt.commission) || !approx(e.ECNFee, tt.ecn) {
+				t.Errorf("commission, ECN fee = %v, %v; want %v, %v", e.Commission, e.ECNFee, tt.commission, tt.ecn)
+			}
+		})
+	}
+}
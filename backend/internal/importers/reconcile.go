@@ -0,0 +1,48 @@
+package importers
+
+import (
+	"math"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// pnlTolerance absorbs rounding in broker-reported P&L
+const pnlTolerance = 0.01
+
+// PnLDifference is a closed trade whose calculated P&L disagrees with the P&L
+// the broker reported for it
+type PnLDifference struct {
+	Index      int     `json:"index"`
+	Symbol     string  `json:"symbol"`
+	PnL        float64 `json:"pnl"`
+	BrokerPnL  float64 `json:"broker_pnl"`
+	Difference float64 `json:"difference"`
+}
+
+// ReconcilePnL compares each closed trade's P&L, calculated the same way as the
+// calculate_pnl() trigger, with the broker's realized P&L. Trades the broker
+// reported no P&L for are not compared.
+func ReconcilePnL(trades []models.Trade) []PnLDifference {
+	differences := make([]PnLDifference, 0)
+
+	for i, trade := range trades {
+		if trade.PnL == nil || trade.BrokerPnL == nil {
+			continue
+		}
+
+		difference := *trade.PnL - *trade.BrokerPnL
+		if math.Abs(difference) <= pnlTolerance {
+			continue
+		}
+
+		differences = append(differences, PnLDifference{
+			Index:      i,
+			Symbol:     trade.Symbol,
+			PnL:        *trade.PnL,
+			BrokerPnL:  *trade.BrokerPnL,
+			Difference: difference,
+		})
+	}
+
+	return differences
+}
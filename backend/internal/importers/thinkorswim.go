@@ -0,0 +1,435 @@
+package importers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// TOSStatement is the result of parsing a thinkorswim account statement
+type TOSStatement struct {
+	Account    string
+	Executions []models.Execution
+	Cash       []CashActivity
+	// Unmatched are closing fills for positions opened before the statement
+	// period that no earlier import left open. They cannot form a trade on
+	// their own and are left out.
+	Unmatched []models.Execution
+}
+
+const (
+	tosTradeHistory = "Account Trade History"
+	tosCashBalance  = "Cash Balance"
+
+	// optionMultiplier is the number of shares one equity option contract covers
+	optionMultiplier = 100
+)
+
+var tosAccountPattern = regexp.MustCompile(`Account Statement for (\S+)`)
+
+// tosTradeColumns are the trade history columns every statement must have
+var tosTradeColumns = []string{"exec time", "side", "qty", "symbol", "price"}
+
+// ParseTOSStatement parses a thinkorswim (Schwab) "Account Statement" CSV. The
+// file is a series of sections, each a title line followed by a header row.
+// Fills come from "Account Trade History", where the legs of a spread follow the
+// first leg without an exec time of their own. "Cash Balance" supplies each
+// fill's commissions and fees, matched by time, along with deposits,
+// withdrawals, dividends and interest. Times are read in loc. Closing fills are
+// matched against the positions in open, the trades earlier imports left open,
+// as well as those opened in the statement.
+func ParseTOSStatement(r io.Reader, loc *time.Location, open []models.Trade) (*TOSStatement, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+
+	result := &TOSStatement{
+		Executions: make([]models.Execution, 0),
+		Cash:       make([]CashActivity, 0),
+		Unmatched:  make([]models.Execution, 0),
+	}
+
+	sections := make(map[string][][]string)
+	current := ""
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read statement: %w", err)
+		}
+
+		fields := trimTrailingEmpty(record)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// A line with a single field starts a section
+		if len(fields) == 1 {
+			title := strings.TrimSpace(strings.TrimPrefix(fields[0], "\ufeff"))
+			if m := tosAccountPattern.FindStringSubmatch(title); m != nil {
+				result.Account = m[1]
+			}
+			current = title
+			continue
+		}
+		sections[current] = append(sections[current], record)
+	}
+
+	history, ok := sections[tosTradeHistory]
+	if !ok {
+		return nil, fmt.Errorf("missing %q section: not a thinkorswim account statement", tosTradeHistory)
+	}
+
+	closing, err := parseTOSTradeHistory(history, result, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	fees := make(map[int64]tosFees)
+	if cash, ok := sections[tosCashBalance]; ok {
+		if err := parseTOSCashBalance(cash, result, fees, loc); err != nil {
+			return nil, err
+		}
+	}
+
+	allocateTOSFees(result, fees)
+	dropUnmatchedCloses(result, closing, open)
+	setCashAccount(result.Cash, result.Account)
+
+	return result, nil
+}
+
+// parseTOSTradeHistory reads the fills. It returns whether each one closes a position.
+func parseTOSTradeHistory(rows [][]string, result *TOSStatement, loc *time.Location) ([]bool, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range tosTradeColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%s: missing column %q", tosTradeHistory, name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	closing := make([]bool, 0)
+	var lastTime time.Time
+
+	for n, record := range rows[1:] {
+		row := n + 1
+
+		// Spread legs after the first have no exec time
+		at := lastTime
+		if value := field(record, "exec time"); value != "" {
+			parsed, err := parseTOSTime(value, loc)
+			if err != nil {
+				return nil, fmt.Errorf("%s row %d: %w", tosTradeHistory, row, err)
+			}
+			at = parsed
+		}
+		if at.IsZero() {
+			return nil, fmt.Errorf("%s row %d: missing exec time", tosTradeHistory, row)
+		}
+		lastTime = at
+
+		side, ok := defaultSides[strings.ToUpper(field(record, "side"))]
+		if !ok {
+			return nil, fmt.Errorf("%s row %d: unknown side %q", tosTradeHistory, row, field(record, "side"))
+		}
+
+		quantity, err := parseAmount(field(record, "qty"))
+		if err != nil {
+			return nil, fmt.Errorf("%s row %d: invalid qty: %w", tosTradeHistory, row, err)
+		}
+
+		price, err := parseAmount(field(record, "price"))
+		if err != nil {
+			return nil, fmt.Errorf("%s row %d: invalid price: %w", tosTradeHistory, row, err)
+		}
+
+		symbol, multiplier, err := tosInstrument(field(record, "symbol"), field(record, "type"), field(record, "exp"), field(record, "strike"), at)
+		if err != nil {
+			return nil, fmt.Errorf("%s row %d: %w", tosTradeHistory, row, err)
+		}
+
+		result.Executions = append(result.Executions, models.Execution{
+			Account:    result.Account,
+			Symbol:     symbol,
+			Side:       side,
+			Quantity:   math.Abs(quantity),
+			Price:      price,
+			ExecutedAt: at,
+			Multiplier: multiplier,
+		})
+		closing = append(closing, strings.EqualFold(field(record, "pos effect"), "TO CLOSE"))
+	}
+
+	return closing, nil
+}
+
+// tosInstrument returns the symbol and multiplier for a trade history row. Options
+// get their OCC symbol from the underlying, expiry, strike and CALL or PUT type;
+// futures such as "/ESZ25" get their exchange code and point value.
+func tosInstrument(symbol, instrumentType, expiry, strike string, at time.Time) (string, float64, error) {
+	symbol = strings.ToUpper(symbol)
+
+	switch strings.ToUpper(instrumentType) {
+	case "CALL", "PUT":
+		// Expiries read like "17 JAN 25", sometimes followed by a series note
+		parts := strings.Fields(expiry)
+		if len(parts) < 3 {
+			return "", 0, fmt.Errorf("invalid option expiry %q", expiry)
+		}
+		expires, err := time.Parse("2 Jan 06", strings.Join(parts[:3], " "))
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid option expiry %q", expiry)
+		}
+
+		strikePrice, err := parseAmount(strike)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid option strike %q", strike)
+		}
+
+		return optionSymbol(symbol, expires, instrumentType, strikePrice), optionMultiplier, nil
+	case "FUTURE":
+		contract, ok, err := ParseFuturesContract(symbol, at)
+		if err != nil {
+			return "", 0, err
+		}
+		if !ok {
+			return "", 0, fmt.Errorf("invalid futures symbol %q", symbol)
+		}
+		return contract.Symbol(), contract.PointValue, nil
+	}
+
+	return symbol, 1, nil
+}
+
+// tosFees are the charges on the cash balance rows for one trade time
+type tosFees struct {
+	at         time.Time
+	misc       float64
+	commission float64
+}
+
+// parseTOSCashBalance collects trade fees by time and the rest of the cash activity
+func parseTOSCashBalance(rows [][]string, result *TOSStatement, fees map[int64]tosFees, loc *time.Location) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	amount := func(record []string, name string, row int) (float64, error) {
+		value := field(record, name)
+		if value == "" {
+			return 0, nil
+		}
+		parsed, err := parseAmount(value)
+		if err != nil {
+			return 0, fmt.Errorf("%s row %d: invalid %s: %w", tosCashBalance, row, name, err)
+		}
+		return parsed, nil
+	}
+
+	for n, record := range rows[1:] {
+		row := n + 1
+
+		// The balance and total rows have no date and time to read
+		at, err := parseTOSTime(field(record, "date")+" "+field(record, "time"), loc)
+		if err != nil {
+			continue
+		}
+
+		misc, err := amount(record, "misc fees", row)
+		if err != nil {
+			return err
+		}
+		commission, err := amount(record, "commissions & fees", row)
+		if err != nil {
+			return err
+		}
+		total, err := amount(record, "amount", row)
+		if err != nil {
+			return err
+		}
+
+		description := field(record, "description")
+		reference := strings.Trim(strings.TrimPrefix(field(record, "ref #"), "="), `"`)
+
+		var activity CashActivityType
+		switch strings.ToUpper(field(record, "type")) {
+		case "TRD":
+			f := fees[at.Unix()]
+			f.at = at
+			f.misc += misc
+			f.commission += commission
+			fees[at.Unix()] = f
+			continue
+		case "EFN", "WIR", "ACH", "JRN":
+			activity = fundingActivity(total)
+		case "DOI", "INT":
+			activity = CashDividend
+			if strings.Contains(strings.ToUpper(description), "INTEREST") {
+				activity = CashInterest
+			}
+		default:
+			if !strings.Contains(strings.ToUpper(description), "FEE") {
+				continue
+			}
+			activity = CashFee
+			total += misc + commission
+		}
+
+		if total == 0 {
+			continue
+		}
+		result.Cash = append(result.Cash, CashActivity{
+			Type:        activity,
+			Amount:      total,
+			Description: description,
+			Reference:   reference,
+			OccurredAt:  at,
+		})
+	}
+
+	return nil
+}
+
+// allocateTOSFees charges each trade time's fees to the fills executed then, in
+// proportion to their quantity. Fees for a time with no fills are kept as cash activity.
+func allocateTOSFees(result *TOSStatement, fees map[int64]tosFees) {
+	quantities := make(map[int64]float64)
+	for _, e := range result.Executions {
+		quantities[e.ExecutedAt.Unix()] += e.Quantity
+	}
+
+	for i := range result.Executions {
+		e := &result.Executions[i]
+		f, ok := fees[e.ExecutedAt.Unix()]
+		if !ok {
+			continue
+		}
+		share := e.Quantity / quantities[e.ExecutedAt.Unix()]
+		e.Commission = debit(f.commission) * share
+		e.MiscFee = debit(f.misc) * share
+	}
+
+	times := make([]int64, 0)
+	for at := range fees {
+		if _, ok := quantities[at]; !ok && fees[at].misc+fees[at].commission != 0 {
+			times = append(times, at)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	for _, at := range times {
+		result.Cash = append(result.Cash, CashActivity{
+			Type:        CashFee,
+			Amount:      fees[at].misc + fees[at].commission,
+			Description: "Trade fees with no matching fill",
+			OccurredAt:  fees[at].at,
+		})
+	}
+}
+
+// dropUnmatchedCloses moves closing fills that have no open position before them,
+// either from the statement or carried over in open, to Unmatched. Without their
+// opening fills they would start a trade the wrong way round.
+func dropUnmatchedCloses(result *TOSStatement, closing []bool, open []models.Trade) {
+	order := make([]int, len(result.Executions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return result.Executions[order[a]].ExecutedAt.Before(result.Executions[order[b]].ExecutedAt)
+	})
+
+	net := make(map[string]float64)
+	for _, trade := range open {
+		for _, e := range trade.Executions {
+			if e.Account != result.Account {
+				continue
+			}
+			if e.Side == models.SideSell {
+				net[e.Symbol] -= e.Quantity
+			} else {
+				net[e.Symbol] += e.Quantity
+			}
+		}
+	}
+
+	drop := make(map[int]bool)
+	for _, i := range order {
+		e := result.Executions[i]
+		signed := e.Quantity
+		if e.Side == models.SideSell {
+			signed = -signed
+		}
+
+		if closing[i] && (math.Abs(net[e.Symbol]) < 1e-9 || (net[e.Symbol] > 0) == (signed > 0)) {
+			drop[i] = true
+			continue
+		}
+		net[e.Symbol] += signed
+	}
+
+	kept := make([]models.Execution, 0, len(result.Executions))
+	for i, e := range result.Executions {
+		if drop[i] {
+			result.Unmatched = append(result.Unmatched, e)
+			continue
+		}
+		kept = append(kept, e)
+	}
+	result.Executions = kept
+}
+
+func parseTOSTime(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"1/2/06 15:04:05", "1/2/2006 15:04:05"} {
+		if at, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return at, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
+
+func trimTrailingEmpty(record []string) []string {
+	end := len(record)
+	for end > 0 && strings.TrimSpace(record[end-1]) == "" {
+		end--
+	}
+	return record[:end]
+}
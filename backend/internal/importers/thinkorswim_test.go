@@ -0,0 +1,88 @@
+package importers
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata/golden")
+
+// assertGolden compares got, encoded as indented JSON, with testdata/golden/<name>.json
+func assertGolden(t *testing.T, name string, got interface{}) {
+	t.Helper()
+
+	encoded, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	encoded = append(encoded, '\n')
+
+	path := filepath.Join("testdata", "golden", name+".json")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, encoded, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run go test -update to create it): %v", err)
+	}
+	if !bytes.Equal(encoded, want) {
+		t.Errorf("result does not match %s (run go test -update to accept)\ngot:\n%s", path, encoded)
+	}
+}
+
+// openFixture opens a file under testdata, closed when the test ends
+func openFixture(t *testing.T, parts ...string) *os.File {
+	t.Helper()
+	f, err := os.Open(filepath.Join(append([]string{"testdata"}, parts...)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestParseTOSStatementGolden(t *testing.T) {
+	statement, err := ParseTOSStatement(openFixture(t, "thinkorswim", "statement.csv"), time.UTC, nil)
+	if err != nil {
+		t.Fatalf("ParseTOSStatement: %v", err)
+	}
+
+	assertGolden(t, "thinkorswim/statement", statement)
+}
+
+func TestParseTOSStatementClosesOpenPosition(t *testing.T) {
+	opened := time.Date(2025, 1, 3, 15, 0, 0, 0, time.UTC)
+	open := []models.Trade{
+		{Executions: []models.Execution{
+			// Another account's position must not let the close through
+			{Account: "987654321", Symbol: "AAPL", Side: models.SideBuy, Quantity: 100, ExecutedAt: opened},
+			{Account: "123456789", Symbol: "MSFT", Side: models.SideBuy, Quantity: 50, ExecutedAt: opened},
+		}},
+	}
+
+	statement, err := ParseTOSStatement(openFixture(t, "thinkorswim", "statement.csv"), time.UTC, open)
+	if err != nil {
+		t.Fatalf("ParseTOSStatement: %v", err)
+	}
+
+	if len(statement.Unmatched) != 0 {
+		t.Errorf("unmatched = %d fills, want 0: the MSFT close matches the open position", len(statement.Unmatched))
+	}
+	if n := len(statement.Executions); n != 5 || statement.Executions[4].Symbol != "MSFT" {
+		t.Errorf("executions = %d, want 5 ending with the MSFT close", n)
+	}
+}
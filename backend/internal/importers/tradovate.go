@@ -0,0 +1,205 @@
+package importers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// Column names differ between Tradovate report versions, so each field lists
+// every name it has been exported under
+var (
+	tradovateContract   = []string{"contract", "symbol"}
+	tradovateSide       = []string{"b/s", "side", "action"}
+	tradovateQuantity   = []string{"quantity", "qty", "filled qty", "filledqty"}
+	tradovatePrice      = []string{"price", "avg fill price", "avgprice"}
+	tradovateTime       = []string{"timestamp", "fill time", "date/time"}
+	tradovateFillID     = []string{"fill id", "fillid", "_id", "id"}
+	tradovateOrderID    = []string{"order id", "orderid"}
+	tradovateCommission = []string{"commission", "fees"}
+)
+
+// ParseTradovate parses a Tradovate Fills or Performance CSV export, told apart
+// by their columns. Fills become executions as they are. Each Performance row is
+// a buy and a sell paired by Tradovate, and becomes those two executions with
+// Tradovate's P&L for the pair kept on the later one. Contract codes such as
+// "MNQH6" are converted to their point value. Times are read in loc.
+func ParseTradovate(r io.Reader, loc *time.Location) ([]models.Execution, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	record, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	header := newCSVHeader(record)
+	performance := header.has("buyfillid")
+	if !performance {
+		for _, names := range [][]string{tradovateContract, tradovateSide, tradovateQuantity, tradovatePrice, tradovateTime} {
+			if !header.has(names...) {
+				return nil, fmt.Errorf("missing column %q: not a Tradovate fills or performance export", names[0])
+			}
+		}
+	}
+
+	executions := make([]models.Execution, 0)
+	line := 1
+
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if header.get(record, tradovateContract...) == "" {
+			continue
+		}
+
+		if performance {
+			pair, err := tradovatePair(header, record, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			executions = append(executions, pair...)
+			continue
+		}
+
+		execution, err := tradovateFill(header, record, loc)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		executions = append(executions, execution)
+	}
+
+	return executions, nil
+}
+
+func tradovateFill(header csvHeader, record []string, loc *time.Location) (models.Execution, error) {
+	rawSide := header.get(record, tradovateSide...)
+	side, ok := defaultSides[strings.ToUpper(rawSide)]
+	if !ok {
+		return models.Execution{}, fmt.Errorf("unknown side %q", rawSide)
+	}
+
+	quantity, err := parseAmount(header.get(record, tradovateQuantity...))
+	if err != nil || quantity == 0 {
+		return models.Execution{}, fmt.Errorf("invalid quantity %q", header.get(record, tradovateQuantity...))
+	}
+
+	price, err := parseAmount(header.get(record, tradovatePrice...))
+	if err != nil {
+		return models.Execution{}, fmt.Errorf("invalid price %q", header.get(record, tradovatePrice...))
+	}
+
+	at, err := parseFuturesTime(header.get(record, tradovateTime...), loc)
+	if err != nil {
+		return models.Execution{}, err
+	}
+
+	symbol, multiplier, err := futuresInstrument(header.get(record, tradovateContract...), at)
+	if err != nil {
+		return models.Execution{}, err
+	}
+
+	var commission float64
+	if value := header.get(record, tradovateCommission...); value != "" {
+		commission, err = parseAmount(value)
+		if err != nil {
+			return models.Execution{}, fmt.Errorf("invalid commission %q", value)
+		}
+	}
+
+	return models.Execution{
+		Account:    header.get(record, "account"),
+		Symbol:     symbol,
+		Side:       side,
+		Quantity:   math.Abs(quantity),
+		Price:      price,
+		ExecutedAt: at,
+		OrderID:    header.get(record, tradovateOrderID...),
+		FillID:     header.get(record, tradovateFillID...),
+		Commission: commission,
+		Multiplier: multiplier,
+	}, nil
+}
+
+// tradovatePair converts a Performance row into its buy and sell executions. A
+// fill can be split across several rows, so each execution is identified by its
+// own fill ID together with the one it was paired with.
+func tradovatePair(header csvHeader, record []string, loc *time.Location) ([]models.Execution, error) {
+	quantity, err := parseAmount(header.get(record, "qty"))
+	if err != nil || quantity <= 0 {
+		return nil, fmt.Errorf("invalid qty %q", header.get(record, "qty"))
+	}
+
+	buyPrice, err := parseAmount(header.get(record, "buyprice"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid buyPrice %q", header.get(record, "buyprice"))
+	}
+	sellPrice, err := parseAmount(header.get(record, "sellprice"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sellPrice %q", header.get(record, "sellprice"))
+	}
+
+	boughtAt, err := parseFuturesTime(header.get(record, "boughttimestamp"), loc)
+	if err != nil {
+		return nil, err
+	}
+	soldAt, err := parseFuturesTime(header.get(record, "soldtimestamp"), loc)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol, multiplier, err := futuresInstrument(header.get(record, tradovateContract...), boughtAt)
+	if err != nil {
+		return nil, err
+	}
+
+	buyFill := header.get(record, "buyfillid")
+	sellFill := header.get(record, "sellfillid")
+
+	buy := models.Execution{
+		Symbol:     symbol,
+		Side:       models.SideBuy,
+		Quantity:   quantity,
+		Price:      buyPrice,
+		ExecutedAt: boughtAt,
+		FillID:     buyFill + "-" + sellFill,
+		Multiplier: multiplier,
+	}
+	sell := models.Execution{
+		Symbol:     symbol,
+		Side:       models.SideSell,
+		Quantity:   quantity,
+		Price:      sellPrice,
+		ExecutedAt: soldAt,
+		FillID:     sellFill + "-" + buyFill,
+		Multiplier: multiplier,
+	}
+
+	if value := header.get(record, "pnl"); value != "" {
+		pnl, err := parseAmount(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pnl %q", value)
+		}
+		// The later fill is the one that closed the pair
+		if soldAt.Before(boughtAt) {
+			buy.RealizedPnL = &pnl
+		} else {
+			sell.RealizedPnL = &pnl
+		}
+	}
+
+	return []models.Execution{buy, sell}, nil
+}
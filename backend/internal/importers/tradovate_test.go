@@ -0,0 +1,30 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTradovateGolden(t *testing.T) {
+	for _, name := range []string{"fills", "performance"} {
+		t.Run(name, func(t *testing.T) {
+			executions, err := ParseTradovate(openFixture(t, "tradovate", name+".csv"), time.UTC)
+			if err != nil {
+				t.Fatalf("ParseTradovate: %v", err)
+			}
+
+			assertGolden(t, "tradovate/"+name, executions)
+		})
+	}
+}
+
+func TestParseTradovateRejectsUnknownContract(t *testing.T) {
+	csv := "Fill ID,Timestamp,B/S,Contract,Quantity,Price\n" +
+		"1,2025-12-01 09:30:04,Buy,QQZ5,1,100\n"
+
+	_, err := ParseTradovate(strings.NewReader(csv), time.UTC)
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("error = %v, want an unknown contract error on line 2", err)
+	}
+}
@@ -0,0 +1,62 @@
+package importers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// ValidationError describes a parsed trade that should not be imported as is
+type ValidationError struct {
+	Index   int    `json:"index"`
+	Symbol  string `json:"symbol"`
+	Message string `json:"message"`
+}
+
+// ValidateTrades checks parsed trades before they are committed
+func ValidateTrades(trades []models.Trade) []ValidationError {
+	errs := make([]ValidationError, 0)
+
+	for i, trade := range trades {
+		invalid := func(format string, args ...interface{}) {
+			errs = append(errs, ValidationError{Index: i, Symbol: trade.Symbol, Message: fmt.Sprintf(format, args...)})
+		}
+
+		if strings.TrimSpace(trade.Symbol) == "" {
+			invalid("symbol is required")
+		}
+		if trade.TradeType != models.TradeLong && trade.TradeType != models.TradeShort {
+			invalid("trade_type must be LONG or SHORT")
+		}
+		if trade.Quantity <= 0 {
+			invalid("quantity must be positive")
+		}
+		if trade.EntryPrice <= 0 {
+			invalid("entry_price must be positive")
+		}
+		if trade.Multiplier < 0 {
+			invalid("multiplier cannot be negative")
+		}
+		if trade.ExitPrice != nil && *trade.ExitPrice < 0 {
+			invalid("exit_price cannot be negative")
+		}
+		if trade.OpenedAt.IsZero() {
+			invalid("opened_at is required")
+		}
+		if trade.ClosedAt != nil && trade.ClosedAt.Before(trade.OpenedAt) {
+			invalid("closed_at is before opened_at")
+		}
+		if (trade.ExitPrice == nil) != (trade.ClosedAt == nil) {
+			invalid("exit_price and closed_at must be set together")
+		}
+
+		for _, e := range trade.Executions {
+			if e.Quantity <= 0 || e.Price < 0 {
+				invalid("execution at %s has an invalid quantity or price", e.ExecutedAt.Format("15:04:05"))
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,155 @@
+package importers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// xlsxSignature is the start of every zip archive, which is what an .xlsx file is
+var xlsxSignature = []byte("PK\x03\x04")
+
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline struct {
+				Text string `xml:"t"`
+			} `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSXRows returns the cell text of each row in the first worksheet of an
+// Excel workbook. Cells keep their column position, so a row with a value in
+// column C only has empty strings before it. Numbers and dates are returned as
+// stored, which for dates is the Excel serial day number.
+func readXLSXRows(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workbook: %w", err)
+	}
+
+	files := make(map[string]*zip.File)
+	sheets := make([]string, 0)
+	for _, file := range archive.File {
+		files[file.Name] = file
+		if strings.HasPrefix(file.Name, "xl/worksheets/sheet") && strings.HasSuffix(file.Name, ".xml") {
+			sheets = append(sheets, file.Name)
+		}
+	}
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("workbook has no worksheets")
+	}
+	sort.Slice(sheets, func(i, j int) bool {
+		return xlsxSheetNumber(sheets[i]) < xlsxSheetNumber(sheets[j])
+	})
+
+	var shared []string
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		var strs xlsxSharedStrings
+		if err := decodeZipXML(file, &strs); err != nil {
+			return nil, fmt.Errorf("failed to read shared strings: %w", err)
+		}
+		for _, item := range strs.Items {
+			text := item.Text
+			for _, run := range item.Runs {
+				text += run.Text
+			}
+			shared = append(shared, text)
+		}
+	}
+
+	var sheet xlsxWorksheet
+	if err := decodeZipXML(files[sheets[0]], &sheet); err != nil {
+		return nil, fmt.Errorf("failed to read worksheet: %w", err)
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, sheetRow := range sheet.Rows {
+		row := make([]string, 0)
+		for i, cell := range sheetRow.Cells {
+			column := i
+			if cell.Ref != "" {
+				column = xlsxColumn(cell.Ref)
+				if column < 0 || column >= xlsxMaxColumns {
+					return nil, fmt.Errorf("cell %q: invalid cell reference", cell.Ref)
+				}
+			}
+			for len(row) <= column {
+				row = append(row, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(shared) {
+					return nil, fmt.Errorf("cell %s: invalid shared string %q", cell.Ref, cell.Value)
+				}
+				row[column] = shared[index]
+			case "inlineStr":
+				row[column] = cell.Inline.Text
+			default:
+				row[column] = cell.Value
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func decodeZipXML(file *zip.File, v interface{}) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return xml.NewDecoder(io.LimitReader(reader, maxUncompressedXLSXPart)).Decode(v)
+}
+
+// maxUncompressedXLSXPart bounds how much of a single workbook part is read, so
+// a small upload cannot expand into an unbounded amount of memory
+const maxUncompressedXLSXPart = 256 << 20
+
+// xlsxMaxColumns is the number of columns in an Excel worksheet, A to XFD
+const xlsxMaxColumns = 16384
+
+// xlsxColumn converts the letters of a cell reference such as "AB12" to a
+// zero-based column. It returns -1 when the reference has no column letters and
+// xlsxMaxColumns when they are past the last column.
+func xlsxColumn(ref string) int {
+	column := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+		if column > xlsxMaxColumns {
+			return xlsxMaxColumns
+		}
+	}
+	return column - 1
+}
+
+// xlsxSheetNumber returns N for "xl/worksheets/sheetN.xml"
+func xlsxSheetNumber(name string) int {
+	n, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "xl/worksheets/sheet"), ".xml"))
+	return n
+}
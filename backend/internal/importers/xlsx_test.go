@@ -0,0 +1,135 @@
+package importers
+
+import (
+	"archive/zip"
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// workbook zips the named parts into an .xlsx file
+func workbook(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// worksheet wraps rows of <c> elements in a worksheet part
+func worksheet(rows ...string) string {
+	var sheet strings.Builder
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for _, row := range rows {
+		sheet.WriteString("<row>" + row + "</row>")
+	}
+	sheet.WriteString("</sheetData></worksheet>")
+	return sheet.String()
+}
+
+func TestReadXLSXRows(t *testing.T) {
+	data := workbook(t, map[string]string{
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>Symbol</t></si><si><r><t>EUR</t></r><r><t>USD</t></r></si></sst>`,
+		// The second sheet is ignored
+		"xl/worksheets/sheet2.xml": worksheet(`<c r="A1" t="inlineStr"><is><t>other</t></is></c>`),
+		"xl/worksheets/sheet1.xml": worksheet(
+			`<c r="A1" t="s"><v>0</v></c><c r="C1" t="inlineStr"><is><t>Volume</t></is></c>`,
+			`<c r="A2" t="s"><v>1</v></c><c r="C2"><v>0.5</v></c>`,
+		),
+	})
+
+	rows, err := readXLSXRows(data)
+	if err != nil {
+		t.Fatalf("readXLSXRows: %v", err)
+	}
+
+	want := [][]string{{"Symbol", "", "Volume"}, {"EURUSD", "", "0.5"}}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %q, want %q", rows, want)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q, want %q", i+1, rows[i], want[i])
+		}
+	}
+}
+
+func TestReadXLSXRowsRejectsBadCells(t *testing.T) {
+	tests := map[string]string{
+		"no column letters": `<c r="12"><v>1</v></c>`,
+		"past column XFD":   `<c r="XFE1"><v>1</v></c>`,
+		"huge column":       `<c r="ZZZZZZZZZZZZ1"><v>1</v></c>`,
+		"missing string":    `<c r="A1" t="s"><v>3</v></c>`,
+	}
+	for name, cell := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := workbook(t, map[string]string{"xl/worksheets/sheet1.xml": worksheet(cell)})
+			if _, err := readXLSXRows(data); err == nil {
+				t.Error("readXLSXRows succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestParseMetaTraderWorkbook(t *testing.T) {
+	cell := func(ref, text string) string {
+		return `<c r="` + ref + `" t="inlineStr"><is><t>` + text + `</t></is></c>`
+	}
+	number := func(ref, value string) string {
+		return `<c r="` + ref + `"><v>` + value + `</v></c>`
+	}
+
+	// An MT5 report saved as a workbook, where times are Excel serial dates
+	data := workbook(t, map[string]string{
+		"xl/worksheets/sheet1.xml": worksheet(
+			cell("A1", "Account:")+cell("B1", "5001234 (USD, Example-Server, real, Hedge)"),
+			cell("A2", "Positions"),
+			cell("A3", "Time")+cell("B3", "Position")+cell("C3", "Symbol")+cell("D3", "Type")+cell("E3", "Volume")+
+				cell("F3", "Price")+cell("G3", "Time")+cell("H3", "Price")+cell("I3", "Commission")+
+				cell("J3", "Swap")+cell("K3", "Profit"),
+			number("A4", "45971.385439814815")+cell("B4", "7001")+cell("C4", "EURUSD")+cell("D4", "buy")+
+				number("E4", "0.1")+number("F4", "1.1542")+number("G4", "45971.5")+number("H4", "1.1561")+
+				number("I4", "-0.7")+number("J4", "0")+number("K4", "19"),
+		),
+	})
+
+	statement, err := ParseMetaTraderStatement(bytes.NewReader(data), time.UTC, nil)
+	if err != nil {
+		t.Fatalf("ParseMetaTraderStatement: %v", err)
+	}
+
+	if statement.Platform != "MT5" || statement.Account != "5001234" || statement.Currency != "USD" {
+		t.Errorf("platform, account, currency = %s, %s, %s; want MT5, 5001234, USD", statement.Platform, statement.Account, statement.Currency)
+	}
+	if len(statement.Executions) != 2 {
+		t.Fatalf("executions = %d, want 2", len(statement.Executions))
+	}
+	opened, closed := statement.Executions[0], statement.Executions[1]
+	if want := time.Date(2025, 11, 10, 9, 15, 2, 0, time.UTC); !opened.ExecutedAt.Equal(want) {
+		t.Errorf("opened at = %v, want %v", opened.ExecutedAt, want)
+	}
+	if want := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC); !closed.ExecutedAt.Equal(want) {
+		t.Errorf("closed at = %v, want %v", closed.ExecutedAt, want)
+	}
+	if closed.RealizedPnL == nil || !approx(*closed.RealizedPnL, 18.3) || closed.ForeignQuote {
+		t.Errorf("closing execution = %v pnl, foreign %v; want 18.3 in the account currency", closed.RealizedPnL, closed.ForeignQuote)
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
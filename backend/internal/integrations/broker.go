@@ -0,0 +1,123 @@
+// Package integrations fetches executions from broker APIs. Each broker
+// implements BrokerIntegration and is added to a Registry; FetchTrades turns any
+// broker's executions into trades.
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// Credentials are what a user enters to connect a broker
+type Credentials struct {
+	Site     string // Broker host, for brokers with one site per firm
+	Username string
+	Password string
+}
+
+// Capabilities describe what a broker integration supports
+type Capabilities struct {
+	Accounts     bool `json:"accounts"`      // One login may hold several accounts
+	RequiresSite bool `json:"requires_site"` // Credentials must include a site
+	Commissions  bool `json:"commissions"`   // Executions carry commissions
+}
+
+// BrokerIntegration is a client for one broker. A client is created for a single
+// set of credentials, authenticated once and closed when the fetch is done.
+type BrokerIntegration interface {
+	Provider() models.IntegrationProvider
+	Name() string
+	Capabilities() Capabilities
+
+	// Authenticate logs in with the client's credentials
+	Authenticate(ctx context.Context) error
+	// ListAccounts returns the account IDs the login can read
+	ListAccounts(ctx context.Context) ([]string, error)
+	// FetchExecutions returns an account's executions between two YYYY-MM-DD
+	// dates, inclusive. The outcome of every day is passed to report as it is
+	// fetched; a failed day is reported rather than returned as an error.
+	FetchExecutions(ctx context.Context, accountID, fromDate, toDate string, report func(DayReport)) ([]models.Execution, error)
+	// Close ends the session
+	Close() error
+}
+
+// Factory creates a broker client for a set of credentials
+type Factory func(credentials Credentials) BrokerIntegration
+
+// ProviderInfo describes a registered broker
+type ProviderInfo struct {
+	Provider     models.IntegrationProvider `json:"provider"`
+	Name         string                     `json:"name"`
+	Capabilities Capabilities               `json:"capabilities"`
+}
+
+// Registry holds the broker integrations the API can use
+type Registry struct {
+	factories map[models.IntegrationProvider]Factory
+}
+
+func NewRegistry(factories ...Factory) *Registry {
+	registry := &Registry{factories: make(map[models.IntegrationProvider]Factory)}
+	for _, factory := range factories {
+		registry.Register(factory)
+	}
+	return registry
+}
+
+// Register adds a broker, replacing any earlier one for the same provider
+func (r *Registry) Register(factory Factory) {
+	r.factories[factory(Credentials{}).Provider()] = factory
+}
+
+// New creates a client for the provider
+func (r *Registry) New(provider models.IntegrationProvider, credentials Credentials) (BrokerIntegration, error) {
+	factory, ok := r.factories[provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported broker %q", provider)
+	}
+	return factory(credentials), nil
+}
+
+// Lookup finds a provider by its ID, ignoring case so URL segments can be lowercase
+func (r *Registry) Lookup(id string) (ProviderInfo, bool) {
+	factory, ok := r.factories[models.IntegrationProvider(strings.ToUpper(id))]
+	if !ok {
+		return ProviderInfo{}, false
+	}
+	return describe(factory(Credentials{})), true
+}
+
+// Providers lists the registered brokers by name
+func (r *Registry) Providers() []ProviderInfo {
+	providers := make([]ProviderInfo, 0, len(r.factories))
+	for _, factory := range r.factories {
+		providers = append(providers, describe(factory(Credentials{})))
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name < providers[j].Name
+	})
+	return providers
+}
+
+func describe(broker BrokerIntegration) ProviderInfo {
+	return ProviderInfo{
+		Provider:     broker.Provider(),
+		Name:         broker.Name(),
+		Capabilities: broker.Capabilities(),
+	}
+}
+
+// ValidateCredentials checks the credentials have what the provider needs
+func (p ProviderInfo) ValidateCredentials(credentials Credentials) error {
+	if credentials.Username == "" || credentials.Password == "" {
+		return fmt.Errorf("username and password are required")
+	}
+	if p.Capabilities.RequiresSite && credentials.Site == "" {
+		return fmt.Errorf("%s requires a site", p.Name)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tradepulse/api/internal/models"
+	"github.com/tradepulse/api/internal/positions"
+)
+
+// FetchOptions control a FetchTrades call
+type FetchOptions struct {
+	// Accounts limits the fetch to these account IDs; all accounts when empty
+	Accounts []string
+	// OpenPositions are trades still open from an earlier import. Fills that
+	// close them are attached to the same trade instead of starting a new one.
+	OpenPositions []models.Trade
+	// OnProgress, when set, is called as days are fetched
+	OnProgress func(done, total int)
+	// Concurrency is how many accounts are fetched at once; defaultConcurrency when zero
+	Concurrency int
+}
+
+// defaultConcurrency bounds parallel account fetches so a firm login with many
+// accounts does not flood the broker
+const defaultConcurrency = 4
+
+// FetchTrades logs in to the broker, fetches executions for every selected
+// account between two YYYY-MM-DD dates and builds them into trades. Missing
+// dates default to the last month. Days that fail are listed in the report
+// and do not fail the fetch, so callers must check report.Failed.
+func FetchTrades(ctx context.Context, broker BrokerIntegration, fromDate, toDate string, opts FetchOptions) ([]models.Trade, *FetchReport, error) {
+	if err := broker.Authenticate(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer broker.Close()
+
+	accountIds, err := broker.ListAccounts(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(opts.Accounts) > 0 {
+		accountIds, err = selectAccounts(accountIds, opts.Accounts)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	report := newFetchReport()
+	if len(accountIds) == 0 {
+		return []models.Trade{}, report, nil
+	}
+
+	// Set date range defaults if not provided
+	if fromDate == "" {
+		fromDate = time.Now().AddDate(0, -1, 0).Format("2006-01-02") // Last month
+	}
+	if toDate == "" {
+		toDate = time.Now().Format("2006-01-02") // Today
+	}
+
+	// Walk every account's fills through one position engine so positions held
+	// overnight carry across days instead of being cut at each report boundary
+	engine := positions.NewEngine()
+	engine.Seed(opts.OpenPositions)
+
+	total := len(accountIds) * reportDays(fromDate, toDate)
+	var mu sync.Mutex
+	record := func(day DayReport) {
+		mu.Lock()
+		defer mu.Unlock()
+		report.add(day)
+		if opts.OnProgress != nil {
+			opts.OnProgress(len(report.Days), total)
+		}
+	}
+
+	executions, err := fetchAccounts(ctx, broker, accountIds, fromDate, toDate, opts.Concurrency, record)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Process accounts in a fixed order so the same fills always build the same trades
+	for _, accountExecutions := range executions {
+		engine.Process(accountExecutions)
+	}
+	report.sort()
+
+	return engine.Trades(), report, nil
+}
+
+// fetchAccounts fetches each account's executions on a bounded pool of workers.
+// The result is in the order of accountIds. The first error cancels the rest.
+func fetchAccounts(ctx context.Context, broker BrokerIntegration, accountIds []string, fromDate, toDate string, concurrency int, record func(DayReport)) ([][]models.Execution, error) {
+	if concurrency < 1 {
+		concurrency = defaultConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]models.Execution, len(accountIds))
+	errs := make([]error, len(accountIds))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(accountIds); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], errs[i] = broker.FetchExecutions(ctx, accountIds[i], fromDate, toDate, record)
+				if errs[i] != nil {
+					cancel()
+				}
+			}
+		}()
+	}
+
+	for i := range accountIds {
+		if ctx.Err() != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	// Report the error that stopped the fetch, not the cancellations it caused
+	var firstErr error
+	for i, err := range errs {
+		if err != nil && (firstErr == nil || errors.Is(firstErr, context.Canceled)) {
+			firstErr = fmt.Errorf("failed to fetch account %s: %w", accountIds[i], err)
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// selectAccounts keeps the wanted accounts, failing if any is not available
+func selectAccounts(available, wanted []string) ([]string, error) {
+	known := make(map[string]bool, len(available))
+	for _, id := range available {
+		known[id] = true
+	}
+
+	for _, id := range wanted {
+		if !known[id] {
+			return nil, fmt.Errorf("account %s is not available to this login", id)
+		}
+	}
+	return wanted, nil
+}
+
+// reportDays counts the days between two dates, inclusive
+func reportDays(fromDate, toDate string) int {
+	startDate, err1 := time.Parse("2006-01-02", fromDate)
+	endDate, err2 := time.Parse("2006-01-02", toDate)
+	if err1 != nil || err2 != nil || endDate.Before(startDate) {
+		return 0
+	}
+	return int(endDate.Sub(startDate).Hours()/24) + 1
+}
@@ -1,7 +1,9 @@
 package integrations
 
 import (
+	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,19 +15,25 @@ import (
 	"github.com/tradepulse/api/internal/models"
 )
 
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
+// PropReportsConfig tunes how hard the client pushes a PropReports site
+type PropReportsConfig struct {
+	RequestsPerSecond float64 // 0 disables rate limiting
+	MaxRetries        int     // Retries for transient failures on each request
 }
 
+// DefaultPropReportsConfig keeps well inside the limits PropReports sites enforce
+var DefaultPropReportsConfig = PropReportsConfig{RequestsPerSecond: 2, MaxRetries: 3}
+
+// PropReportsClient implements BrokerIntegration for PropReports, the reporting
+// platform used by many prop firms. Each firm has its own site.
 type PropReportsClient struct {
-	BaseURL  string
-	Username string
-	Password string
-	client   *http.Client
-	token    string
+	BaseURL    string
+	Username   string
+	Password   string
+	MaxRetries int
+	client     *http.Client
+	limiter    *rateLimiter
+	token      string
 }
 
 // PropReports CSV fill record format
@@ -54,6 +62,16 @@ type PropReportsFill struct {
 	PropReportsId string // PropReports Id
 }
 
+// NewPropReports returns the registry Factory for PropReports
+func NewPropReports(config PropReportsConfig) Factory {
+	return func(credentials Credentials) BrokerIntegration {
+		client := NewPropReportsClient(credentials.Site, credentials.Username, credentials.Password)
+		client.MaxRetries = config.MaxRetries
+		client.limiter = newRateLimiter(config.RequestsPerSecond)
+		return client
+	}
+}
+
 func NewPropReportsClient(site, username, password string) *PropReportsClient {
 	baseURL := fmt.Sprintf("https://%s", site)
 
@@ -64,32 +82,60 @@ func NewPropReportsClient(site, username, password string) *PropReportsClient {
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		limiter: newRateLimiter(0),
 	}
 }
 
-// Login authenticates with PropReports and stores the token
-func (c *PropReportsClient) Login() error {
+// post calls a PropReports API action and returns the response body. Requests
+// are rate limited, and transient failures are retried with backoff.
+func (c *PropReportsClient) post(ctx context.Context, action string, data url.Values) ([]byte, error) {
 	apiURL := fmt.Sprintf("%s/api.php", c.BaseURL)
+	data.Set("action", action)
+
+	var body []byte
+	err := withRetries(ctx, c.MaxRetries, func() error {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
+		if err != nil {
+			return fmt.Errorf("failed to build %s request: %w", action, err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return &RequestError{Action: action, Message: err.Error(), Retryable: true}
+		}
+		defer resp.Body.Close()
+
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return &RequestError{Action: action, Message: "failed to read response: " + err.Error(), Retryable: true}
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return statusError(action, resp, body)
+		}
+		return nil
+	})
+
+	return body, err
+}
 
+// Login authenticates with PropReports and stores the token
+func (c *PropReportsClient) Login(ctx context.Context) error {
 	data := url.Values{}
-	data.Set("action", "login")
 	data.Set("user", c.Username)
 	data.Set("password", c.Password)
 
-	resp, err := c.client.PostForm(apiURL, data)
+	body, err := c.post(ctx, "login", data)
 	if err != nil {
-		return fmt.Errorf("failed to login: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("login failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read login response: %w", err)
+		return err
 	}
 
 	token := strings.TrimSpace(string(body))
@@ -102,47 +148,32 @@ func (c *PropReportsClient) Login() error {
 }
 
 // Logout expires the token
-func (c *PropReportsClient) Logout() error {
+func (c *PropReportsClient) Logout(ctx context.Context) error {
 	if c.token == "" {
 		return nil
 	}
 
-	apiURL := fmt.Sprintf("%s/api.php", c.BaseURL)
 	data := url.Values{}
-	data.Set("action", "logout")
 	data.Set("token", c.token)
 
-	resp, err := c.client.PostForm(apiURL, data)
-	if err != nil {
-		return fmt.Errorf("failed to logout: %w", err)
-	}
-	defer resp.Body.Close()
-
+	_, err := c.post(ctx, "logout", data)
 	c.token = ""
-	return nil
+	return err
 }
 
 // GetAccounts fetches the list of accounts
-func (c *PropReportsClient) GetAccounts() ([]string, error) {
-	apiURL := fmt.Sprintf("%s/api.php", c.BaseURL)
-
+func (c *PropReportsClient) GetAccounts(ctx context.Context) ([]string, error) {
 	data := url.Values{}
-	data.Set("action", "accounts")
 	data.Set("token", c.token)
 
-	resp, err := c.client.PostForm(apiURL, data)
+	body, err := c.post(ctx, "accounts", data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch accounts: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("accounts request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, err
 	}
 
-	// Parse CSV response
-	csvReader := csv.NewReader(resp.Body)
+	// Parse CSV response; the "Page X/Y" trailer has fewer fields than the rows
+	csvReader := csv.NewReader(strings.NewReader(string(body)))
+	csvReader.FieldsPerRecord = -1
 	records, err := csvReader.ReadAll()
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse accounts CSV: %w", err)
@@ -164,51 +195,45 @@ func (c *PropReportsClient) GetAccounts() ([]string, error) {
 	return accountIds, nil
 }
 
-// FetchTrades fetches trades (fills) from PropReports
-func (c *PropReportsClient) FetchTrades(fromDate, toDate string) ([]models.Trade, error) {
-	// Login first
-	if err := c.Login(); err != nil {
-		return nil, err
-	}
-	defer c.Logout()
-
-	// Get list of accounts
-	accountIds, err := c.GetAccounts()
-	if err != nil {
-		return nil, err
-	}
+func (c *PropReportsClient) Provider() models.IntegrationProvider {
+	return models.ProviderPropReports
+}
 
-	if len(accountIds) == 0 {
-		return []models.Trade{}, nil
-	}
+func (c *PropReportsClient) Name() string {
+	return "PropReports"
+}
 
-	// Set date range defaults if not provided
-	if fromDate == "" {
-		fromDate = time.Now().AddDate(0, -1, 0).Format("2006-01-02") // Last month
-	}
-	if toDate == "" {
-		toDate = time.Now().Format("2006-01-02") // Today
-	}
+func (c *PropReportsClient) Capabilities() Capabilities {
+	return Capabilities{Accounts: true, RequiresSite: true, Commissions: true}
+}
 
-	// Fetch fills for each account
-	allTrades := make([]models.Trade, 0)
+func (c *PropReportsClient) Authenticate(ctx context.Context) error {
+	return c.Login(ctx)
+}
 
-	for _, accountId := range accountIds {
-		trades, err := c.fetchFillsForAccount(accountId, fromDate, toDate)
-		if err != nil {
-			// Log error but continue with other accounts
-			continue
-		}
-		allTrades = append(allTrades, trades...)
-	}
+func (c *PropReportsClient) ListAccounts(ctx context.Context) ([]string, error) {
+	return c.GetAccounts(ctx)
+}
 
-	return allTrades, nil
+// Close logs out. It runs after the fetch, so it gets its own short deadline
+// rather than the fetch's possibly canceled context.
+func (c *PropReportsClient) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return c.Logout(ctx)
 }
 
-// fetchFillsForAccount fetches fills for a specific account using the detailed report
-func (c *PropReportsClient) fetchFillsForAccount(accountId, fromDate, toDate string) ([]models.Trade, error) {
-	apiURL := fmt.Sprintf("%s/api.php", c.BaseURL)
+// reportWindowDays is the longest range PropReports accepts for a detailed
+// report; longer ranges are rejected with a "less than a week" message
+const reportWindowDays = 7
+
+// errReportRange is returned when PropReports rejects a report's date range
+var errReportRange = errors.New("report range rejected")
 
+// FetchExecutions fetches fills for an account using the detailed report, one
+// request per week. Each day's outcome goes to report; only a canceled context
+// stops the fetch early.
+func (c *PropReportsClient) FetchExecutions(ctx context.Context, accountId, fromDate, toDate string, report func(DayReport)) ([]models.Execution, error) {
 	// Parse dates
 	startDate, err := time.Parse("2006-01-02", fromDate)
 	if err != nil {
@@ -219,93 +244,124 @@ func (c *PropReportsClient) fetchFillsForAccount(accountId, fromDate, toDate str
 		return nil, fmt.Errorf("invalid end date: %w", err)
 	}
 
-	// PropReports detailed report requires max 1 week of data
-	// We'll query day by day for reliability
-	allTrades := make([]models.Trade, 0)
-
-	for currentDate := startDate; !currentDate.After(endDate); currentDate = currentDate.AddDate(0, 0, 1) {
-		dateStr := currentDate.Format("2006-01-02")
+	allExecutions := make([]models.Execution, 0)
 
-		data := url.Values{}
-		data.Set("action", "report")
-		data.Set("type", "detailed")
-		data.Set("token", c.token)
-		data.Set("accountId", accountId)
-		data.Set("startDate", dateStr)
-		data.Set("endDate", dateStr)
+	for windowStart := startDate; !windowStart.After(endDate); windowStart = windowStart.AddDate(0, 0, reportWindowDays) {
+		windowEnd := windowStart.AddDate(0, 0, reportWindowDays-1)
+		if windowEnd.After(endDate) {
+			windowEnd = endDate
+		}
 
-		resp, err := c.client.PostForm(apiURL, data)
+		executions, err := c.fetchWindow(ctx, accountId, windowStart, windowEnd, report)
 		if err != nil {
-			fmt.Printf("DEBUG: Error fetching report for %s: %v\n", dateStr, err)
-			continue // Skip this day on error
+			return nil, err
 		}
+		allExecutions = append(allExecutions, executions...)
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			fmt.Printf("DEBUG: Non-OK status for %s: %d\n", dateStr, resp.StatusCode)
-			resp.Body.Close()
-			continue // Skip this day
-		}
+	return allExecutions, nil
+}
 
-		// Read response body
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
+// fetchWindow fetches one report window and reports each of its days. A window
+// PropReports rejects is fetched again one day at a time.
+func (c *PropReportsClient) fetchWindow(ctx context.Context, accountId string, start, end time.Time, report func(DayReport)) ([]models.Execution, error) {
+	executions, err := c.fetchDetailedReport(ctx, accountId, start, end)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 
-		if err != nil {
-			fmt.Printf("DEBUG: Error reading body for %s: %v\n", dateStr, err)
-			continue
+	if errors.Is(err, errReportRange) && end.After(start) {
+		dayExecutions := make([]models.Execution, 0)
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			executions, err := c.fetchWindow(ctx, accountId, day, day, report)
+			if err != nil {
+				return nil, err
+			}
+			dayExecutions = append(dayExecutions, executions...)
 		}
+		return dayExecutions, nil
+	}
 
-		bodyStr := string(body)
+	// Count fills per day so every day in the window gets its own outcome
+	perDay := make(map[string]int)
+	for _, execution := range executions {
+		perDay[execution.ExecutedAt.Format("2006-01-02")]++
+	}
 
-		// Check for "No data available" message
-		if strings.Contains(bodyStr, "No data available") {
-			fmt.Printf("DEBUG: No data available for %s\n", dateStr)
-			continue
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		dateStr := day.Format("2006-01-02")
+		result := DayReport{Account: accountId, Date: dateStr, Status: DayOK, Executions: perDay[dateStr]}
+		switch {
+		case err != nil:
+			result.Status, result.Error = DayFailed, err.Error()
+		case result.Executions == 0:
+			result.Status = DayNoData
 		}
+		report(result)
+	}
 
-		// Check for week limit error
-		if strings.Contains(bodyStr, "less than a week") {
-			fmt.Printf("DEBUG: Week limit error for %s\n", dateStr)
-			continue
-		}
+	if err != nil {
+		// The failure is in the report; carry on with the next window
+		return nil, nil
+	}
+	return executions, nil
+}
 
-		fmt.Printf("DEBUG: Processing %s, body length: %d bytes\n", dateStr, len(bodyStr))
-		fmt.Printf("DEBUG: First 200 chars: %s\n", bodyStr[:min(200, len(bodyStr))])
+// fetchDetailedReport fetches the detailed report for a range of days. It
+// returns nil executions when PropReports has no data for the range.
+func (c *PropReportsClient) fetchDetailedReport(ctx context.Context, accountId string, start, end time.Time) ([]models.Execution, error) {
+	data := url.Values{}
+	data.Set("type", "detailed")
+	data.Set("token", c.token)
+	data.Set("accountId", accountId)
+	data.Set("startDate", start.Format("2006-01-02"))
+	data.Set("endDate", end.Format("2006-01-02"))
 
-		// Parse CSV - configure reader to handle multiline fields and variable field counts
-		csvReader := csv.NewReader(strings.NewReader(bodyStr))
-		csvReader.LazyQuotes = true
-		csvReader.FieldsPerRecord = -1 // Allow variable number of fields
-		csvReader.TrimLeadingSpace = true
+	body, err := c.post(ctx, "report", data)
+	if err != nil {
+		return nil, err
+	}
 
-		records, err := csvReader.ReadAll()
-		if err != nil {
-			fmt.Printf("DEBUG: CSV parse error for %s: %v\n", dateStr, err)
-			continue
-		}
+	bodyStr := string(body)
 
-		fmt.Printf("DEBUG: Parsed %d CSV records for %s\n", len(records), dateStr)
+	// Check for "No data available" message
+	if strings.Contains(bodyStr, "No data available") {
+		return nil, nil
+	}
 
-		// Convert fills to trades for this day
-		dayTrades, err := c.convertDetailedReportToTrades(records, currentDate)
-		if err != nil {
-			fmt.Printf("DEBUG: Convert error for %s: %v\n", dateStr, err)
-			continue
-		}
+	// Check for week limit error
+	if strings.Contains(bodyStr, "less than a week") {
+		return nil, fmt.Errorf("%w: %s", errReportRange, truncate(strings.TrimSpace(bodyStr), 200))
+	}
+
+	return c.readDetailedReport(bodyStr, accountId, start)
+}
 
-		fmt.Printf("DEBUG: Generated %d trades for %s\n", len(dayTrades), dateStr)
-		allTrades = append(allTrades, dayTrades...)
+// readDetailedReport parses the CSV body of a detailed report
+func (c *PropReportsClient) readDetailedReport(body, accountId string, startDate time.Time) ([]models.Execution, error) {
+	// Parse CSV - configure reader to handle multiline fields and variable field counts
+	csvReader := csv.NewReader(strings.NewReader(body))
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1 // Allow variable number of fields
+	csvReader.TrimLeadingSpace = true
+
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse report CSV: %w", err)
 	}
 
-	return allTrades, nil
+	return c.parseDetailedReport(records, accountId, startDate), nil
 }
 
-// convertDetailedReportToTrades converts PropReports detailed report to Trade models
-func (c *PropReportsClient) convertDetailedReportToTrades(records [][]string, tradeDate time.Time) ([]models.Trade, error) {
-	trades := make([]models.Trade, 0)
+// parseDetailedReport converts a PropReports detailed report into executions.
+// A report covering several days starts each day with a date line; fills before
+// the first date line belong to startDate.
+func (c *PropReportsClient) parseDetailedReport(records [][]string, accountId string, startDate time.Time) []models.Execution {
+	executions := make([]models.Execution, 0)
 
 	var currentSymbol string
-	var fills []PropReportsFill
+	tradeDate := startDate
+	columns := defaultDetailedColumns
 
 	for _, record := range records {
 		if len(record) == 0 {
@@ -317,17 +373,15 @@ func (c *PropReportsClient) convertDetailedReportToTrades(records [][]string, tr
 		if len(record) == 1 && record[0] != "" {
 			field := strings.TrimSpace(record[0])
 
-			// Skip date lines
+			// Date lines start the next day's fills
 			if strings.Contains(field, "/") {
+				if date, err := time.Parse("01/02/2006", field); err == nil {
+					tradeDate = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, startDate.Location())
+					currentSymbol = ""
+				}
 				continue
 			}
 
-			// Process previous symbol's fills if any
-			if currentSymbol != "" && len(fills) > 0 {
-				symbolTrades := c.processFillsForSymbol(currentSymbol, fills, tradeDate)
-				trades = append(trades, symbolTrades...)
-			}
-
 			// Start new symbol - extract just the symbol part if it has a description
 			// "BCG - Binah Capital Group, Inc." -> "BCG"
 			symbolParts := strings.Split(field, " - ")
@@ -336,13 +390,12 @@ func (c *PropReportsClient) convertDetailedReportToTrades(records [][]string, tr
 			} else {
 				currentSymbol = field
 			}
-
-			fills = make([]PropReportsFill, 0)
 			continue
 		}
 
 		// Check if this is the header row (contains "Time,Order Id,Fill Id,...")
 		if strings.Contains(record[0], "Time") {
+			columns = readDetailedHeader(record)
 			continue
 		}
 
@@ -356,321 +409,118 @@ func (c *PropReportsClient) convertDetailedReportToTrades(records [][]string, tr
 		// Parse fill record (Time, OrderId, FillId, Route, Liq, B/S, Qty, Price, ...)
 		if len(record) >= 8 && currentSymbol != "" {
 			fill := PropReportsFill{
-				DateTime: record[0],
-				Side:     record[5], // B/S column
-				Qty:      record[6],
+				DateTime: columns.get(record, "time"),
+				Account:  accountId,
+				OrderId:  columns.get(record, "order id"),
+				FillId:   columns.get(record, "fill id"),
+				Route:    columns.get(record, "route"),
+				Liq:      columns.get(record, "liq"),
+				Side:     columns.get(record, "b/s"),
+				Qty:      columns.get(record, "qty"),
 				Symbol:   currentSymbol,
-				Price:    record[7],
+				Price:    columns.get(record, "price"),
+				Comm:     columns.get(record, "comm"),
+				EcnFee:   columns.get(record, "ecn fee"),
+				SEC:      columns.get(record, "sec"),
+				TAF:      columns.get(record, "taf"),
+				NSCC:     columns.get(record, "nscc"),
+				Clr:      columns.get(record, "clr"),
+				Misc:     columns.get(record, "misc"),
+				Currency: columns.get(record, "currency"),
 			}
-			if len(record) > 10 {
-				fill.Comm = record[10] // Comm column
+			if execution, ok := fill.toExecution(tradeDate); ok {
+				executions = append(executions, execution)
 			}
-			fills = append(fills, fill)
 		}
 	}
 
-	// Process last symbol
-	if currentSymbol != "" && len(fills) > 0 {
-		symbolTrades := c.processFillsForSymbol(currentSymbol, fills, tradeDate)
-		trades = append(trades, symbolTrades...)
-	}
-
-	return trades, nil
+	return executions
 }
 
-// processFillsForSymbol groups fills by symbol and converts to trades
-func (c *PropReportsClient) processFillsForSymbol(symbol string, fills []PropReportsFill, tradeDate time.Time) []models.Trade {
-	// Separate buys and sells
-	var buys, sells []PropReportsFill
-	for _, fill := range fills {
-		if fill.Side == "B" {
-			buys = append(buys, fill)
-		} else if fill.Side == "S" {
-			sells = append(sells, fill)
-		}
+// toExecution converts a fill record into an execution. The detailed report only
+// carries the time of day, so it is combined with the report date.
+func (f PropReportsFill) toExecution(tradeDate time.Time) (models.Execution, bool) {
+	var side models.ExecutionSide
+	switch f.Side {
+	case "B":
+		side = models.SideBuy
+	case "S", "T":
+		side = models.SideSell
+	default:
+		return models.Execution{}, false
 	}
 
-	// Calculate totals
-	var buyQty, sellQty float64
-	var buyTotal, sellTotal float64
-	var totalFees float64
-	var firstBuyTime, firstSellTime, lastBuyTime, lastSellTime time.Time
-
-	for i, buy := range buys {
-		qty, _ := strconv.ParseFloat(buy.Qty, 64)
-		price, _ := strconv.ParseFloat(buy.Price, 64)
-		comm, _ := strconv.ParseFloat(buy.Comm, 64)
-
-		buyQty += qty
-		buyTotal += qty * price
-		totalFees += comm
-
-		// Parse time (HH:MM:SS format, combine with trade date)
-		timeStr := buy.DateTime
-		if t, err := time.Parse("15:04:05", timeStr); err == nil {
-			fullTime := time.Date(tradeDate.Year(), tradeDate.Month(), tradeDate.Day(),
-				t.Hour(), t.Minute(), t.Second(), 0, tradeDate.Location())
-			if i == 0 {
-				firstBuyTime = fullTime
-			}
-			lastBuyTime = fullTime
-		}
-	}
-
-	for i, sell := range sells {
-		qty, _ := strconv.ParseFloat(sell.Qty, 64)
-		price, _ := strconv.ParseFloat(sell.Price, 64)
-		comm, _ := strconv.ParseFloat(sell.Comm, 64)
-
-		sellQty += qty
-		sellTotal += qty * price
-		totalFees += comm
-
-		// Parse time
-		timeStr := sell.DateTime
-		if t, err := time.Parse("15:04:05", timeStr); err == nil {
-			fullTime := time.Date(tradeDate.Year(), tradeDate.Month(), tradeDate.Day(),
-				t.Hour(), t.Minute(), t.Second(), 0, tradeDate.Location())
-			if i == 0 {
-				firstSellTime = fullTime
-			}
-			lastSellTime = fullTime
+	executedAt, err := time.Parse("01/02/2006 15:04:05", f.DateTime)
+	if err != nil {
+		t, err := time.Parse("15:04:05", f.DateTime)
+		if err != nil {
+			return models.Execution{}, false
 		}
+		executedAt = time.Date(tradeDate.Year(), tradeDate.Month(), tradeDate.Day(),
+			t.Hour(), t.Minute(), t.Second(), 0, tradeDate.Location())
 	}
 
-	// Create trade
-	trades := make([]models.Trade, 0)
-
-	if len(buys) > 0 && len(sells) > 0 {
-		// Complete round trip
-		var tradeType models.TradeType
-		var entryPrice, exitPrice float64
-		var quantity float64
-		var openTime, closeTime time.Time
-
-		if !firstBuyTime.IsZero() && !firstSellTime.IsZero() && firstBuyTime.Before(firstSellTime) {
-			// Long trade (buy first, sell later)
-			tradeType = models.TradeLong
-			entryPrice = buyTotal / buyQty
-			exitPrice = sellTotal / sellQty
-			quantity = buyQty
-			openTime = firstBuyTime
-			closeTime = lastSellTime
-		} else {
-			// Short trade (sell first, buy later)
-			tradeType = models.TradeShort
-			entryPrice = sellTotal / sellQty
-			exitPrice = buyTotal / buyQty
-			quantity = sellQty
-			openTime = firstSellTime
-			closeTime = lastBuyTime
-		}
+	qty, _ := strconv.ParseFloat(f.Qty, 64)
+	price, _ := strconv.ParseFloat(f.Price, 64)
+
+	return models.Execution{
+		Account:     f.Account,
+		Symbol:      f.Symbol,
+		Side:        side,
+		Quantity:    qty,
+		Price:       price,
+		ExecutedAt:  executedAt,
+		Route:       f.Route,
+		Liquidity:   f.Liq,
+		OrderID:     f.OrderId,
+		FillID:      f.FillId,
+		Commission:  parseFee(f.Comm),
+		ECNFee:      parseFee(f.EcnFee),
+		SECFee:      parseFee(f.SEC),
+		TAFFee:      parseFee(f.TAF),
+		NSCCFee:     parseFee(f.NSCC),
+		ClearingFee: parseFee(f.Clr),
+		MiscFee:     parseFee(f.Misc),
+	}, true
+}
 
-		// Calculate P&L
-		var pnl float64
-		if tradeType == models.TradeLong {
-			pnl = (exitPrice - entryPrice) * quantity - totalFees
-		} else {
-			pnl = (entryPrice - exitPrice) * quantity - totalFees
-		}
+// detailedColumns maps lowercase detailed report column names to their positions
+type detailedColumns map[string]int
 
-		trade := models.Trade{
-			Symbol:     symbol,
-			TradeType:  tradeType,
-			Quantity:   quantity,
-			EntryPrice: entryPrice,
-			ExitPrice:  &exitPrice,
-			OpenedAt:   openTime,
-			ClosedAt:   &closeTime,
-			PnL:        &pnl,
-			Fees:       totalFees,
-		}
+// defaultDetailedColumns is the detailed report layout, used until a header row is seen
+var defaultDetailedColumns = detailedColumns{
+	"time": 0, "order id": 1, "fill id": 2, "route": 3, "liq": 4, "b/s": 5, "qty": 6, "price": 7,
+	"comm": 10, "ecn fee": 11, "sec": 12, "taf": 13, "nscc": 14, "clr": 15, "misc": 16,
+}
 
-		trades = append(trades, trade)
+func readDetailedHeader(record []string) detailedColumns {
+	columns := make(detailedColumns, len(record))
+	for i, name := range record {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
 	}
-
-	return trades
+	return columns
 }
 
-// convertFillsToTrades converts PropReports fill records (CSV rows) into Trade models
-// PropReports returns individual fills, but we need to group them into trades (positions)
-// NOTE: This is the old method, kept for compatibility
-func (c *PropReportsClient) convertFillsToTrades(records [][]string) ([]models.Trade, error) {
-	// Group fills by symbol + order to reconstruct trades
-	type TradeKey struct {
-		Symbol string
-		Date   string
+// get returns the named column of a record, or "" when the report lacks it
+func (d detailedColumns) get(record []string, name string) string {
+	i, ok := d[name]
+	if !ok || i >= len(record) {
+		return ""
 	}
+	return strings.TrimSpace(record[i])
+}
 
-	fillsBySymbol := make(map[TradeKey][]PropReportsFill)
-
-	for _, record := range records {
-		if len(record) < 7 {
-			continue // Skip incomplete records
-		}
-
-		// Skip "Page X/Y" trailer lines
-		if strings.HasPrefix(record[0], "Page ") {
-			continue
-		}
-
-		fill := PropReportsFill{
-			DateTime: record[0],
-			Account:  record[1],
-			Side:     record[2],
-			Qty:      record[3],
-			Symbol:   record[4],
-			Price:    record[5],
-		}
-
-		// Extract additional fields if present
-		if len(record) > 6 {
-			fill.Route = record[6]
-		}
-		if len(record) > 8 {
-			fill.Comm = record[8]
-		}
-
-		// Parse date to group by day
-		dateTime, err := time.Parse("01/02/2006 15:04:05", fill.DateTime)
-		if err != nil {
-			continue // Skip fills with invalid timestamps
-		}
-
-		key := TradeKey{
-			Symbol: fill.Symbol,
-			Date:   dateTime.Format("2006-01-02"),
-		}
-
-		fillsBySymbol[key] = append(fillsBySymbol[key], fill)
+// parseFee reads a fee amount such as "0.35", "$1,024.50" or "(0.02)". A
+// negative fee is a rebate, typically an ECN credit for adding liquidity.
+func parseFee(value string) float64 {
+	value = strings.NewReplacer("$", "", ",", "").Replace(value)
+	negative := strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")")
+	fee, err := strconv.ParseFloat(strings.Trim(value, "()"), 64)
+	if err != nil {
+		return 0
 	}
-
-	// Convert grouped fills into trades
-	trades := make([]models.Trade, 0)
-
-	for key, fills := range fillsBySymbol {
-		if len(fills) == 0 {
-			continue
-		}
-
-		// Determine if this is a complete round-trip trade
-		var buys, sells []PropReportsFill
-		for _, fill := range fills {
-			if fill.Side == "B" {
-				buys = append(buys, fill)
-			} else if fill.Side == "S" || fill.Side == "T" {
-				sells = append(sells, fill)
-			}
-		}
-
-		// Calculate totals
-		var buyQty, sellQty float64
-		var buyTotal, sellTotal float64
-		var totalFees float64
-
-		for _, buy := range buys {
-			qty, _ := strconv.ParseFloat(buy.Qty, 64)
-			price, _ := strconv.ParseFloat(buy.Price, 64)
-			comm, _ := strconv.ParseFloat(buy.Comm, 64)
-
-			buyQty += qty
-			buyTotal += qty * price
-			totalFees += comm
-		}
-
-		for _, sell := range sells {
-			qty, _ := strconv.ParseFloat(sell.Qty, 64)
-			price, _ := strconv.ParseFloat(sell.Price, 64)
-			comm, _ := strconv.ParseFloat(sell.Comm, 64)
-
-			sellQty += qty
-			sellTotal += qty * price
-			totalFees += comm
-		}
-
-		// Determine trade type and entry
-		var tradeType models.TradeType
-		var entryPrice, exitPrice float64
-		var quantity float64
-		var openTime, closeTime time.Time
-
-		if len(buys) > 0 && len(sells) > 0 {
-			// Complete round trip
-			if buys[0].DateTime < sells[0].DateTime {
-				// Long trade (buy first, sell later)
-				tradeType = models.TradeLong
-				entryPrice = buyTotal / buyQty
-				exitPrice = sellTotal / sellQty
-				quantity = buyQty
-				openTime, _ = time.Parse("01/02/2006 15:04:05", buys[0].DateTime)
-				closeTime, _ = time.Parse("01/02/2006 15:04:05", sells[len(sells)-1].DateTime)
-			} else {
-				// Short trade (sell first, buy later)
-				tradeType = models.TradeShort
-				entryPrice = sellTotal / sellQty
-				exitPrice = buyTotal / buyQty
-				quantity = sellQty
-				openTime, _ = time.Parse("01/02/2006 15:04:05", sells[0].DateTime)
-				closeTime, _ = time.Parse("01/02/2006 15:04:05", buys[len(buys)-1].DateTime)
-			}
-
-			// Calculate P&L
-			var pnl float64
-			if tradeType == models.TradeLong {
-				pnl = (exitPrice - entryPrice) * quantity - totalFees
-			} else {
-				pnl = (entryPrice - exitPrice) * quantity - totalFees
-			}
-
-			trade := models.Trade{
-				Symbol:     key.Symbol,
-				TradeType:  tradeType,
-				Quantity:   quantity,
-				EntryPrice: entryPrice,
-				ExitPrice:  &exitPrice,
-				OpenedAt:   openTime,
-				ClosedAt:   &closeTime,
-				PnL:        &pnl,
-				Fees:       totalFees,
-			}
-
-			trades = append(trades, trade)
-		} else if len(buys) > 0 {
-			// Open long position
-			tradeType = models.TradeLong
-			entryPrice = buyTotal / buyQty
-			quantity = buyQty
-			openTime, _ = time.Parse("01/02/2006 15:04:05", buys[0].DateTime)
-
-			trade := models.Trade{
-				Symbol:     key.Symbol,
-				TradeType:  tradeType,
-				Quantity:   quantity,
-				EntryPrice: entryPrice,
-				OpenedAt:   openTime,
-				Fees:       totalFees,
-			}
-
-			trades = append(trades, trade)
-		} else if len(sells) > 0 {
-			// Open short position
-			tradeType = models.TradeShort
-			entryPrice = sellTotal / sellQty
-			quantity = sellQty
-			openTime, _ = time.Parse("01/02/2006 15:04:05", sells[0].DateTime)
-
-			trade := models.Trade{
-				Symbol:     key.Symbol,
-				TradeType:  tradeType,
-				Quantity:   quantity,
-				EntryPrice: entryPrice,
-				OpenedAt:   openTime,
-				Fees:       totalFees,
-			}
-
-			trades = append(trades, trade)
-		}
+	if negative {
+		return -fee
 	}
-
-	return trades, nil
+	return fee
 }
@@ -0,0 +1,189 @@
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	fakeUsername = "trader"
+	fakePassword = "secret"
+	fakeToken    = "fake-token"
+)
+
+// fakePropReports is an offline PropReports API. It serves the login, accounts,
+// report and logout actions from testdata/propreports, where each account is a
+// directory of daily detailed reports named YYYY-MM-DD.csv. Reports for several
+// days are the daily files joined together, as PropReports returns them.
+type fakePropReports struct {
+	*httptest.Server
+	dir string
+
+	mu sync.Mutex
+	// maxDays is the longest report range accepted; longer ranges get the
+	// "less than a week" rejection
+	maxDays int
+	// failures is how many 503 responses each action gets before succeeding
+	failures map[string]int
+	// calls counts requests per action
+	calls map[string]int
+}
+
+func newFakePropReports(t *testing.T) *fakePropReports {
+	t.Helper()
+
+	fake := &fakePropReports{
+		dir:      filepath.Join("testdata", "propreports"),
+		maxDays:  reportWindowDays,
+		failures: make(map[string]int),
+		calls:    make(map[string]int),
+	}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.handle))
+	t.Cleanup(fake.Close)
+
+	return fake
+}
+
+// client returns a PropReports client pointed at the fake server
+func (f *fakePropReports) client(username, password string) *PropReportsClient {
+	client := NewPropReports(PropReportsConfig{MaxRetries: 2})(Credentials{
+		Site:     "fake.propreports.test",
+		Username: username,
+		Password: password,
+	}).(*PropReportsClient)
+	client.BaseURL = f.URL
+	return client
+}
+
+func (f *fakePropReports) failNext(action string, times int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[action] = times
+}
+
+func (f *fakePropReports) callCount(action string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[action]
+}
+
+func (f *fakePropReports) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/api.php" {
+		http.NotFound(w, r)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	action := r.PostForm.Get("action")
+
+	f.mu.Lock()
+	f.calls[action]++
+	fail := f.failures[action] > 0
+	if fail {
+		f.failures[action]--
+	}
+	f.mu.Unlock()
+
+	if fail {
+		http.Error(w, "Service temporarily unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	if action != "login" && r.PostForm.Get("token") != fakeToken {
+		http.Error(w, "Invalid token", http.StatusForbidden)
+		return
+	}
+
+	switch action {
+	case "login":
+		if r.PostForm.Get("user") != fakeUsername || r.PostForm.Get("password") != fakePassword {
+			http.Error(w, "Invalid username or password", http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, fakeToken)
+	case "logout":
+		fmt.Fprint(w, "OK")
+	case "accounts":
+		f.writeAccounts(w)
+	case "report":
+		f.writeReport(w, r.PostForm.Get("accountId"), r.PostForm.Get("startDate"), r.PostForm.Get("endDate"))
+	default:
+		http.Error(w, "Unknown action", http.StatusBadRequest)
+	}
+}
+
+func (f *fakePropReports) writeAccounts(w http.ResponseWriter) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprintln(w, "Account Id,Account Name")
+	for _, entry := range entries {
+		if entry.IsDir() {
+			fmt.Fprintf(w, "%s,%s Test Account\n", entry.Name(), entry.Name())
+		}
+	}
+	fmt.Fprintln(w, "Page 1/1")
+}
+
+func (f *fakePropReports) writeReport(w http.ResponseWriter, accountId, startDate, endDate string) {
+	start, err1 := time.Parse("2006-01-02", startDate)
+	end, err2 := time.Parse("2006-01-02", endDate)
+	if err1 != nil || err2 != nil || end.Before(start) {
+		http.Error(w, "Invalid date range", http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	maxDays := f.maxDays
+	f.mu.Unlock()
+
+	if int(end.Sub(start).Hours()/24)+1 > maxDays {
+		fmt.Fprint(w, "Please choose a date range of less than a week for the detailed report.")
+		return
+	}
+
+	days := make([]string, 0)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		report, err := os.ReadFile(filepath.Join(f.dir, accountId, day.Format("2006-01-02")+".csv"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		days = append(days, string(report))
+	}
+
+	if len(days) == 0 {
+		fmt.Fprint(w, "No data available for the selected date range.")
+		return
+	}
+	fmt.Fprint(w, strings.Join(days, ""))
+}
+
+// fixtureReports lists the daily report fixtures as account/date pairs
+func fixtureReports(t *testing.T) []string {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "propreports", "*", "*.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,296 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata/golden")
+
+// assertGolden compares got, encoded as indented JSON, with testdata/golden/<name>.json
+func assertGolden(t *testing.T, name string, got interface{}) {
+	t.Helper()
+
+	encoded, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	encoded = append(encoded, '\n')
+
+	path := filepath.Join("testdata", "golden", name+".json")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, encoded, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run go test -update to create it): %v", err)
+	}
+	if !bytes.Equal(encoded, want) {
+		t.Errorf("result does not match %s (run go test -update to accept)\ngot:\n%s", path, encoded)
+	}
+}
+
+// fastRetries shortens the retry backoff for the rest of the test
+func fastRetries(t *testing.T) {
+	first, max := firstBackoff, maxBackoff
+	firstBackoff, maxBackoff = time.Millisecond, 5*time.Millisecond
+	t.Cleanup(func() { firstBackoff, maxBackoff = first, max })
+}
+
+func TestParseDetailedReportGolden(t *testing.T) {
+	for _, path := range fixtureReports(t) {
+		account := filepath.Base(filepath.Dir(path))
+		date := strings.TrimSuffix(filepath.Base(path), ".csv")
+
+		t.Run(account+"/"+date, func(t *testing.T) {
+			body, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reportDate, err := time.Parse("2006-01-02", date)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			executions, err := NewPropReportsClient("", "", "").readDetailedReport(string(body), account, reportDate)
+			if err != nil {
+				t.Fatalf("readDetailedReport: %v", err)
+			}
+
+			assertGolden(t, "propreports/"+account+"_"+date, executions)
+		})
+	}
+}
+
+func TestParseDetailedReportSplitsDays(t *testing.T) {
+	first, err := os.ReadFile(filepath.Join("testdata", "propreports", "ACC1", "2025-11-12.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := os.ReadFile(filepath.Join("testdata", "propreports", "ACC1", "2025-11-13.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The window starts before either report, so each fill's date must come from its date line
+	start := time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)
+	executions, err := NewPropReportsClient("", "", "").readDetailedReport(string(first)+string(second), "ACC1", start)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	days := make(map[string]int)
+	for _, execution := range executions {
+		days[execution.ExecutedAt.Format("2006-01-02")]++
+	}
+	if len(executions) != 5 || days["2025-11-12"] != 3 || days["2025-11-13"] != 2 {
+		t.Errorf("executions by day = %v (total %d), want 3 on 2025-11-12 and 2 on 2025-11-13", days, len(executions))
+	}
+}
+
+func TestParseFee(t *testing.T) {
+	tests := map[string]float64{
+		"0.35":      0.35,
+		"-0.12":     -0.12,
+		"(0.02)":    -0.02,
+		"$1,024.50": 1024.50,
+		"":          0,
+		"n/a":       0,
+	}
+	for value, want := range tests {
+		if got := parseFee(value); got != want {
+			t.Errorf("parseFee(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestFetchTradesGolden(t *testing.T) {
+	fake := newFakePropReports(t)
+
+	trades, report, err := FetchTrades(context.Background(), fake.client(fakeUsername, fakePassword),
+		"2025-11-10", "2025-11-21", FetchOptions{})
+	if err != nil {
+		t.Fatalf("FetchTrades: %v", err)
+	}
+
+	// Two accounts over two weekly windows
+	if calls := fake.callCount("report"); calls != 4 {
+		t.Errorf("report requests = %d, want 4", calls)
+	}
+	if len(report.Days) != 24 || report.OK != 5 || report.NoData != 19 || report.Failed != 0 {
+		t.Errorf("report = %d days, %d ok, %d no data, %d failed; want 24, 5, 19, 0",
+			len(report.Days), report.OK, report.NoData, report.Failed)
+	}
+	if fake.callCount("logout") != 1 {
+		t.Errorf("logout requests = %d, want 1", fake.callCount("logout"))
+	}
+
+	assertGolden(t, "propreports/fetch_trades", trades)
+	assertGolden(t, "propreports/fetch_report", report)
+}
+
+func TestFetchTradesFallsBackToDailyReports(t *testing.T) {
+	fake := newFakePropReports(t)
+	fake.maxDays = 1
+
+	trades, report, err := FetchTrades(context.Background(), fake.client(fakeUsername, fakePassword),
+		"2025-11-10", "2025-11-21", FetchOptions{})
+	if err != nil {
+		t.Fatalf("FetchTrades: %v", err)
+	}
+
+	// Each rejected window is fetched again one day at a time
+	if calls := fake.callCount("report"); calls != 2*(2+12) {
+		t.Errorf("report requests = %d, want %d", calls, 2*(2+12))
+	}
+	if report.Failed != 0 {
+		t.Errorf("failed days = %d, want 0", report.Failed)
+	}
+
+	assertGolden(t, "propreports/fetch_trades", trades)
+}
+
+func TestFetchTradesRetriesTransientErrors(t *testing.T) {
+	fastRetries(t)
+	fake := newFakePropReports(t)
+	fake.failNext("report", 2)
+
+	_, report, err := FetchTrades(context.Background(), fake.client(fakeUsername, fakePassword),
+		"2025-11-10", "2025-11-16", FetchOptions{Accounts: []string{"ACC1"}})
+	if err != nil {
+		t.Fatalf("FetchTrades: %v", err)
+	}
+
+	if report.Failed != 0 || report.OK != 3 {
+		t.Errorf("report = %d ok, %d failed; want 3 ok, 0 failed", report.OK, report.Failed)
+	}
+	if calls := fake.callCount("report"); calls != 3 {
+		t.Errorf("report requests = %d, want 3", calls)
+	}
+}
+
+func TestFetchTradesReportsFailedDays(t *testing.T) {
+	fastRetries(t)
+	fake := newFakePropReports(t)
+	// More failures than the client's two retries, so the first window fails
+	fake.failNext("report", 3)
+
+	trades, report, err := FetchTrades(context.Background(), fake.client(fakeUsername, fakePassword),
+		"2025-11-10", "2025-11-21", FetchOptions{Accounts: []string{"ACC1"}, Concurrency: 1})
+	if err != nil {
+		t.Fatalf("FetchTrades: %v", err)
+	}
+
+	if report.Failed != 7 {
+		t.Errorf("failed days = %d, want 7", report.Failed)
+	}
+	if dates := report.FailedDates(); len(dates) != 7 || dates[0] != "2025-11-10" || dates[6] != "2025-11-16" {
+		t.Errorf("failed dates = %v, want 2025-11-10 through 2025-11-16", dates)
+	}
+	if report.Err() == nil {
+		t.Error("report.Err() = nil, want an error for the failed days")
+	}
+
+	for _, day := range report.Days {
+		if day.Status == DayFailed && !strings.Contains(day.Error, "status 503") {
+			t.Errorf("day %s error = %q, want the 503 response", day.Date, day.Error)
+		}
+	}
+
+	// Only the second window's trade survives
+	if len(trades) != 1 || trades[0].Symbol != "AMZE" || !trades[0].OpenedAt.Equal(time.Date(2025, 11, 17, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("trades = %+v, want the 2025-11-17 AMZE trade only", trades)
+	}
+}
+
+func TestAuthenticateRejectsBadPassword(t *testing.T) {
+	fake := newFakePropReports(t)
+
+	err := fake.client(fakeUsername, "wrong").Authenticate(context.Background())
+
+	var requestErr *RequestError
+	if !errors.As(err, &requestErr) {
+		t.Fatalf("Authenticate error = %v, want a *RequestError", err)
+	}
+	if requestErr.StatusCode != http.StatusForbidden || requestErr.Retryable {
+		t.Errorf("error = %+v, want a non-retryable 403", requestErr)
+	}
+	if calls := fake.callCount("login"); calls != 1 {
+		t.Errorf("login requests = %d, want 1", calls)
+	}
+}
+
+func TestFetchTradesStopsWhenCanceled(t *testing.T) {
+	fake := newFakePropReports(t)
+	client := fake.client(fakeUsername, fakePassword)
+	if err := client.Authenticate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.FetchExecutions(ctx, "ACC1", "2025-11-10", "2025-11-21", func(DayReport) {})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("FetchExecutions error = %v, want context.Canceled", err)
+	}
+	if calls := fake.callCount("report"); calls != 0 {
+		t.Errorf("report requests = %d, want 0", calls)
+	}
+}
+
+func TestFetchTradesBuildsPositions(t *testing.T) {
+	fake := newFakePropReports(t)
+
+	trades, _, err := FetchTrades(context.Background(), fake.client(fakeUsername, fakePassword),
+		"2025-11-10", "2025-11-21", FetchOptions{Accounts: []string{"ACC1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bySymbol := make(map[string]models.Trade)
+	for _, trade := range trades {
+		bySymbol[trade.Symbol+" "+trade.OpenedAt.Format("2006-01-02")] = trade
+	}
+
+	cyph, ok := bySymbol["CYPH 2025-11-10"]
+	if !ok || cyph.TradeType != models.TradeShort || cyph.PnL == nil {
+		t.Fatalf("CYPH trade = %+v, want a closed short", cyph)
+	}
+	// (4.25 - 4.10) * 200 less every itemized fee on both fills
+	wantFees := 1.00 + 0.60 + 0.02 + 0.04 + 1.00 - 0.40 + 0.02 + 0.04 + 0.10
+	if diff := cyph.Fees - wantFees; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("CYPH fees = %v, want %v", cyph.Fees, wantFees)
+	}
+
+	// Bought on the 12th, sold over two days
+	xyz, ok := bySymbol["XYZ 2025-11-12"]
+	if !ok || xyz.ClosedAt == nil || len(xyz.Executions) != 5 {
+		t.Fatalf("XYZ trade = %+v, want one closed trade of 5 fills", xyz)
+	}
+	if got := xyz.ClosedAt.Format("2006-01-02"); got != "2025-11-13" {
+		t.Errorf("XYZ closed on %s, want 2025-11-13", got)
+	}
+
+	if _, ok := bySymbol["BCG 2025-11-10"]; !ok {
+		t.Error("missing the BCG trade from the described symbol line")
+	}
+}
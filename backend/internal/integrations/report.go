@@ -0,0 +1,81 @@
+package integrations
+
+import (
+	"fmt"
+	"sort"
+)
+
+type DayStatus string
+
+const (
+	DayOK     DayStatus = "OK"
+	DayNoData DayStatus = "NO_DATA"
+	DayFailed DayStatus = "ERROR"
+)
+
+// DayReport is the outcome of fetching one account's executions for one day
+type DayReport struct {
+	Account    string    `json:"account"`
+	Date       string    `json:"date"` // YYYY-MM-DD
+	Status     DayStatus `json:"status"`
+	Executions int       `json:"executions"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// FetchReport lists every account and day a fetch covered, so a partial import
+// can be told apart from a complete one
+type FetchReport struct {
+	Days   []DayReport `json:"days"`
+	OK     int         `json:"ok"`
+	NoData int         `json:"no_data"`
+	Failed int         `json:"failed"`
+}
+
+func newFetchReport() *FetchReport {
+	// Initialize to empty slice to avoid null JSON serialization
+	return &FetchReport{Days: make([]DayReport, 0)}
+}
+
+func (r *FetchReport) add(day DayReport) {
+	r.Days = append(r.Days, day)
+	switch day.Status {
+	case DayOK:
+		r.OK++
+	case DayNoData:
+		r.NoData++
+	default:
+		r.Failed++
+	}
+}
+
+// sort orders the days by account, then date
+func (r *FetchReport) sort() {
+	sort.Slice(r.Days, func(i, j int) bool {
+		if r.Days[i].Account != r.Days[j].Account {
+			return r.Days[i].Account < r.Days[j].Account
+		}
+		return r.Days[i].Date < r.Days[j].Date
+	})
+}
+
+// Err summarizes the failed days, or returns nil when every day was fetched
+func (r *FetchReport) Err() error {
+	if r.Failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d account days failed to fetch", r.Failed, len(r.Days))
+}
+
+// FailedDates returns the distinct dates with at least one failed account, in order
+func (r *FetchReport) FailedDates() []string {
+	seen := make(map[string]bool)
+	dates := make([]string, 0)
+	for _, day := range r.Days {
+		if day.Status == DayFailed && !seen[day.Date] {
+			seen[day.Date] = true
+			dates = append(dates, day.Date)
+		}
+	}
+	sort.Strings(dates)
+	return dates
+}
@@ -0,0 +1,135 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Transient failures are retried after firstBackoff, doubling up to maxBackoff.
+// These are variables so tests can shorten them.
+var (
+	firstBackoff = time.Second
+	maxBackoff   = 30 * time.Second
+)
+
+// RequestError is a failed broker API call. Retryable errors are network
+// failures, rate limiting and server errors.
+type RequestError struct {
+	Action     string
+	StatusCode int // 0 when no response was received
+	Message    string
+	Retryable  bool
+	RetryAfter time.Duration // Server's requested wait, if any
+}
+
+func (e *RequestError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s request failed with status %d: %s", e.Action, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%s request failed: %s", e.Action, e.Message)
+}
+
+// statusError builds the error for a non-OK response
+func statusError(action string, resp *http.Response, body []byte) *RequestError {
+	return &RequestError{
+		Action:     action,
+		StatusCode: resp.StatusCode,
+		Message:    truncate(string(body), 200),
+		Retryable:  resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500,
+		RetryAfter: retryAfter(resp),
+	}
+}
+
+// rateLimiter spaces requests at least interval apart
+type rateLimiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	limiter := &rateLimiter{}
+	if requestsPerSecond > 0 {
+		limiter.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	return limiter
+}
+
+// Wait blocks until the next request may be sent or ctx is done
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l.interval == 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	return sleep(ctx, time.Until(at))
+}
+
+// withRetries calls fn until it succeeds, fails with an error that is not
+// retryable, or has been retried maxRetries times
+func withRetries(ctx context.Context, maxRetries int, fn func() error) error {
+	backoff := firstBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		var requestErr *RequestError
+		if err == nil || !errors.As(err, &requestErr) || !requestErr.Retryable || attempt >= maxRetries {
+			return err
+		}
+
+		wait := backoff
+		if requestErr.RetryAfter > wait {
+			wait = requestErr.RetryAfter
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// retryAfter reads a Retry-After header given in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,254 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/database"
+	"github.com/tradepulse/api/internal/integrations"
+	"github.com/tradepulse/api/internal/models"
+	"github.com/tradepulse/api/internal/secrets"
+)
+
+// BrokerImportPayload is the stored part of a broker import job. Jobs for a
+// saved connection name it instead of carrying the login.
+type BrokerImportPayload struct {
+	Provider     models.IntegrationProvider `json:"provider,omitempty"`
+	ConnectionID *uuid.UUID                 `json:"connection_id,omitempty"`
+	Site         string                     `json:"site,omitempty"`
+	Username     string                     `json:"username,omitempty"`
+	AccountID    string                     `json:"account_id,omitempty"` // Optional: one broker account
+	FromDate     string                     `json:"from_date,omitempty"`
+	ToDate       string                     `json:"to_date,omitempty"`
+	Scheduled    bool                       `json:"scheduled,omitempty"` // Queued by the Scheduler
+}
+
+// BrokerImportResult is the stored result of a broker import: the import counts
+// plus the per-account, per-day fetch report
+type BrokerImportResult struct {
+	*database.ImportResult
+	Report *integrations.FetchReport `json:"report"`
+}
+
+// Summary describes the import for the completion notification, calling out
+// days that could not be fetched
+func (r *BrokerImportResult) Summary() string {
+	summary := fmt.Sprintf("Imported %d new and %d updated trades", r.Inserted, r.Updated)
+	if r.Report != nil && r.Report.Failed > 0 {
+		summary += fmt.Sprintf("; %d of %d account days failed and were skipped", r.Report.Failed, len(r.Report.Days))
+	}
+	return summary
+}
+
+// BrokerSecret is the in-memory part of a broker import job
+type BrokerSecret struct {
+	Password string `json:"password"`
+}
+
+// BrokerImport fetches trades from any registered broker and saves them through
+// the idempotent import path. Positions left open by earlier imports from the
+// same broker are carried into the fetch, and each trade is tagged with its account.
+func BrokerImport(db *database.DB, cipher *secrets.Cipher, brokers *integrations.Registry) Handler {
+	return func(ctx context.Context, job *models.Job, progress ProgressFunc) (result interface{}, err error) {
+		var payload BrokerImportPayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("invalid job payload: %w", err)
+		}
+
+		var password string
+		var report *integrations.FetchReport
+		if payload.ConnectionID != nil {
+			connection, err := loadConnection(ctx, db, cipher, *payload.ConnectionID, job.UserID)
+			if err != nil {
+				return nil, err
+			}
+			password = connection.password
+			payload.Provider = connection.Provider
+			payload.Site = connection.Site
+			payload.Username = connection.Username
+			if payload.AccountID == "" {
+				payload.AccountID = connection.AccountID
+			}
+
+			if err := db.UpdateConnectionSyncStatus(ctx, connection.ID, &job.ID, models.SyncRunning, ""); err != nil {
+				return nil, err
+			}
+			defer func() {
+				// A partial fetch saves what it got but still counts as a failed sync
+				syncErr := err
+				if syncErr == nil && report != nil {
+					syncErr = report.Err()
+				}
+
+				status, message := models.SyncSucceeded, ""
+				if syncErr != nil {
+					status, message = models.SyncFailed, syncErr.Error()
+				}
+				// Record the outcome even when shutdown canceled the job
+				if dbErr := db.UpdateConnectionSyncStatus(context.Background(), connection.ID, nil, status, message); dbErr != nil && err == nil {
+					err = dbErr
+				}
+				if payload.Scheduled {
+					through := syncedThrough(payload, report, err)
+					if dbErr := recordScheduledSync(db, connection.IntegrationConnection, through, syncErr); dbErr != nil && err == nil {
+						err = dbErr
+					}
+				}
+			}()
+		} else {
+			var secret BrokerSecret
+			if len(job.Secret) == 0 {
+				return nil, fmt.Errorf("broker credentials are no longer available, please start the import again")
+			}
+			if err := json.Unmarshal(job.Secret, &secret); err != nil {
+				return nil, fmt.Errorf("invalid job credentials: %w", err)
+			}
+			password = secret.Password
+		}
+
+		broker, err := brokers.New(payload.Provider, integrations.Credentials{
+			Site:     payload.Site,
+			Username: payload.Username,
+			Password: password,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		source := models.ImportSource(payload.Provider)
+		openTrades, err := db.ListOpenImportedTrades(ctx, job.UserID, source)
+		if err != nil {
+			return nil, err
+		}
+
+		opts := integrations.FetchOptions{
+			OpenPositions: openTrades,
+			OnProgress: func(done, total int) {
+				progress(done, total, fmt.Sprintf("Fetching %s day %d of %d", broker.Name(), done, total))
+			},
+		}
+		if payload.AccountID != "" {
+			opts.Accounts = []string{payload.AccountID}
+			opts.OpenPositions = tradesForAccount(openTrades, payload.AccountID)
+		}
+
+		var trades []models.Trade
+		trades, report, err = integrations.FetchTrades(ctx, broker, payload.FromDate, payload.ToDate, opts)
+		if err != nil {
+			return nil, err
+		}
+		if report.Failed > 0 {
+			progress(len(report.Days), len(report.Days), report.Err().Error())
+		}
+
+		for i := range trades {
+			trades[i].UserID = job.UserID
+			if account := tradeAccount(trades[i]); account != "" {
+				trades[i].Tags = []string{account}
+			}
+		}
+
+		if len(trades) == 0 {
+			return &BrokerImportResult{
+				ImportResult: &database.ImportResult{
+					TradeIDs:   make([]uuid.UUID, 0),
+					Conflicted: make([]database.ImportConflict, 0),
+				},
+				Report: report,
+			}, nil
+		}
+
+		imported, err := db.BulkCreateTrades(ctx, trades, database.ImportOptions{
+			// Re-fetched positions gain their later fills
+			OnDuplicate: database.DuplicateUpdate,
+			Source:      source,
+			FileName:    payload.Site,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &BrokerImportResult{ImportResult: imported, Report: report}, nil
+	}
+}
+
+// openConnection is a saved connection with its decrypted password
+type openConnection struct {
+	*models.IntegrationConnection
+	password string
+}
+
+func loadConnection(ctx context.Context, db *database.DB, cipher *secrets.Cipher, id, userID uuid.UUID) (*openConnection, error) {
+	if cipher == nil {
+		return nil, fmt.Errorf("saved connections are disabled because no credentials key is configured")
+	}
+
+	connection, err := db.GetIntegrationConnection(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if connection == nil {
+		return nil, fmt.Errorf("integration connection %s no longer exists", id)
+	}
+
+	credentials, err := cipher.OpenCredentials(connection)
+	if err != nil {
+		return nil, err
+	}
+
+	return &openConnection{IntegrationConnection: connection, password: credentials.Password}, nil
+}
+
+// syncedThrough is the last day a scheduled sync fetched completely: the day
+// before its first failed day, or "" when nothing can be counted as synced
+func syncedThrough(payload BrokerImportPayload, report *integrations.FetchReport, err error) string {
+	if err != nil || report == nil {
+		return ""
+	}
+
+	failed := report.FailedDates()
+	if len(failed) == 0 {
+		return payload.ToDate
+	}
+
+	firstFailed, parseErr := time.Parse(syncDateLayout, failed[0])
+	if parseErr != nil {
+		return ""
+	}
+	through := firstFailed.AddDate(0, 0, -1).Format(syncDateLayout)
+	if through < payload.FromDate {
+		return ""
+	}
+	return through
+}
+
+// recordScheduledSync moves the connection's high-water mark up to through and,
+// after a failure, schedules a retry of the remaining days with backoff
+func recordScheduledSync(db *database.DB, connection *models.IntegrationConnection, through string, syncErr error) error {
+	var retryAt *time.Time
+	if syncErr != nil {
+		at := time.Now().Add(retryDelay(connection.SyncFailures + 1))
+		retryAt = &at
+	}
+	return db.RecordScheduledSync(context.Background(), connection.ID, through, retryAt)
+}
+
+func tradesForAccount(trades []models.Trade, account string) []models.Trade {
+	filtered := make([]models.Trade, 0, len(trades))
+	for _, trade := range trades {
+		if tradeAccount(trade) == account {
+			filtered = append(filtered, trade)
+		}
+	}
+	return filtered
+}
+
+func tradeAccount(trade models.Trade) string {
+	if len(trade.Executions) == 0 {
+		return ""
+	}
+	return trade.Executions[0].Account
+}
@@ -0,0 +1,260 @@
+// Package jobs runs long imports and syncs in background workers. Jobs are
+// queued in the jobs table and report progress through the notification bus.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/database"
+	"github.com/tradepulse/api/internal/models"
+	"github.com/tradepulse/api/internal/notifications"
+)
+
+// pollInterval is how often idle workers check the queue for jobs queued
+// by another process
+const pollInterval = 5 * time.Second
+
+// ProgressFunc reports how far a job has got
+type ProgressFunc func(current, total int, message string)
+
+// Handler runs one job and returns its result, stored as JSON
+type Handler func(ctx context.Context, job *models.Job, progress ProgressFunc) (interface{}, error)
+
+// summarizer is implemented by results that can describe themselves in the
+// completion notification
+type summarizer interface {
+	Summary() string
+}
+
+// Runner claims queued jobs and runs them on a fixed number of workers
+type Runner struct {
+	db       *database.DB
+	bus      *notifications.Bus
+	logger   *slog.Logger
+	workers  int
+	handlers map[models.JobType]Handler
+	wake     chan struct{}
+
+	mu sync.Mutex
+	// secrets holds job credentials in memory so they never reach the database
+	secrets map[uuid.UUID]json.RawMessage
+}
+
+func NewRunner(db *database.DB, bus *notifications.Bus, logger *slog.Logger, workers int) *Runner {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &Runner{
+		db:       db,
+		bus:      bus,
+		logger:   logger,
+		workers:  workers,
+		handlers: make(map[models.JobType]Handler),
+		wake:     make(chan struct{}, workers),
+		secrets:  make(map[uuid.UUID]json.RawMessage),
+	}
+}
+
+// Register sets the handler for a job type. It must be called before Run.
+func (r *Runner) Register(jobType models.JobType, handler Handler) {
+	r.handlers[jobType] = handler
+}
+
+// Enqueue queues a job for the user. The payload is stored with the job; the
+// secret is only kept in memory, so a job whose secret is lost to a restart fails.
+func (r *Runner) Enqueue(ctx context.Context, userID uuid.UUID, jobType models.JobType, payload, secret interface{}) (*models.Job, error) {
+	if _, ok := r.handlers[jobType]; !ok {
+		return nil, fmt.Errorf("unknown job type %q", jobType)
+	}
+
+	job := &models.Job{UserID: userID, Type: jobType}
+
+	var err error
+	if job.Payload, err = json.Marshal(payload); err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	var rawSecret json.RawMessage
+	if secret != nil {
+		if rawSecret, err = json.Marshal(secret); err != nil {
+			return nil, fmt.Errorf("failed to encode job secret: %w", err)
+		}
+	}
+
+	// Hold the lock across the insert so a worker cannot claim the job before
+	// its secret is registered
+	r.mu.Lock()
+	err = r.db.CreateJob(ctx, job)
+	if err == nil && rawSecret != nil {
+		r.secrets[job.ID] = rawSecret
+	}
+	r.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Wake an idle worker without blocking when all are busy
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+
+	return job, nil
+}
+
+// Run starts the workers and blocks until ctx is canceled. Jobs left running by
+// a previous process are failed first since their progress is lost.
+func (r *Runner) Run(ctx context.Context) {
+	if count, err := r.db.FailInterruptedJobs(ctx); err != nil {
+		r.logger.Error("Failed to clean up interrupted jobs", "error", err)
+	} else if count > 0 {
+		r.logger.Warn("Failed jobs interrupted by restart", "count", count)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < r.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.work(ctx)
+		}()
+	}
+
+	r.logger.Info("Job runner started", "workers", r.workers)
+	wg.Wait()
+	r.logger.Info("Job runner stopped")
+}
+
+func (r *Runner) work(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain the queue before waiting again
+		for ctx.Err() == nil {
+			r.mu.Lock()
+			job, err := r.db.ClaimNextJob(ctx)
+			if job != nil {
+				job.Secret = r.secrets[job.ID]
+				delete(r.secrets, job.ID)
+			}
+			r.mu.Unlock()
+
+			if err != nil {
+				r.logger.Error("Failed to claim job", "error", err)
+				break
+			}
+			if job == nil {
+				break
+			}
+			r.run(ctx, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) run(ctx context.Context, job *models.Job) {
+	logger := r.logger.With("job_id", job.ID, "job_type", job.Type)
+	logger.Info("Job started")
+
+	progress := func(current, total int, message string) {
+		if err := r.db.UpdateJobProgress(ctx, job.ID, current, total, message); err != nil {
+			logger.Error("Failed to record job progress", "error", err)
+		}
+
+		r.bus.Publish(
+			notifications.NotificationTypeImportProgress,
+			job.UserID,
+			"Import Progress",
+			message,
+			map[string]interface{}{
+				"job_id":   job.ID,
+				"job_type": job.Type,
+				"current":  current,
+				"total":    total,
+			},
+		)
+	}
+
+	result, err := r.execute(ctx, job, progress)
+	if err != nil {
+		logger.Error("Job failed", "error", err)
+
+		// Record the failure even when shutdown canceled the job
+		if dbErr := r.db.FailJob(context.Background(), job.ID, err.Error()); dbErr != nil {
+			logger.Error("Failed to record job failure", "error", dbErr)
+		}
+
+		r.bus.Publish(
+			notifications.NotificationTypeImportFailed,
+			job.UserID,
+			"Import Failed",
+			err.Error(),
+			map[string]interface{}{
+				"job_id":   job.ID,
+				"job_type": job.Type,
+			},
+		)
+		return
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		encoded = json.RawMessage("null")
+		logger.Error("Failed to encode job result", "error", err)
+	}
+
+	if err := r.db.CompleteJob(context.Background(), job.ID, encoded); err != nil {
+		logger.Error("Failed to record job completion", "error", err)
+	}
+
+	logger.Info("Job completed")
+
+	message := "Background import finished"
+	if summary, ok := result.(summarizer); ok {
+		message = summary.Summary()
+	}
+
+	r.bus.Publish(
+		notifications.NotificationTypeImportCompleted,
+		job.UserID,
+		"Import Complete",
+		message,
+		map[string]interface{}{
+			"job_id":   job.ID,
+			"job_type": job.Type,
+			"result":   result,
+		},
+	)
+}
+
+// execute runs the job's handler, turning a panic into a job failure so one
+// bad import cannot take down a worker
+func (r *Runner) execute(ctx context.Context, job *models.Job, progress ProgressFunc) (result interface{}, err error) {
+	handler, ok := r.handlers[job.Type]
+	if !ok {
+		return nil, fmt.Errorf("no handler registered for job type %q", job.Type)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+
+	return handler(ctx, job, progress)
+}
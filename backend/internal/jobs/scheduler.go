@@ -0,0 +1,162 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/tradepulse/api/internal/database"
+	"github.com/tradepulse/api/internal/models"
+)
+
+const (
+	// scheduleInterval is how often the scheduler looks for due syncs
+	scheduleInterval = time.Minute
+
+	// Failed scheduled syncs retry after firstRetryDelay, doubling up to maxRetryDelay
+	firstRetryDelay = 5 * time.Minute
+	maxRetryDelay   = 4 * time.Hour
+
+	syncTimeLayout = "15:04"
+	syncDateLayout = "2006-01-02"
+)
+
+// Scheduler queues broker syncs for connections with a schedule. Each run fetches
+// the days after the connection's synced_through mark up to today in the
+// schedule's timezone, so nothing is fetched twice once a run succeeds.
+type Scheduler struct {
+	db     *database.DB
+	runner *Runner
+	logger *slog.Logger
+}
+
+func NewScheduler(db *database.DB, runner *Runner, logger *slog.Logger) *Scheduler {
+	return &Scheduler{db: db, runner: runner, logger: logger}
+}
+
+// Run checks for due syncs every minute until ctx is canceled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(scheduleInterval)
+	defer ticker.Stop()
+
+	s.logger.Info("Sync scheduler started")
+	for {
+		s.queueDue(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Sync scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) queueDue(ctx context.Context, now time.Time) {
+	connections, err := s.db.ListDueSyncs(ctx, now)
+	if err != nil {
+		s.logger.Error("Failed to list due syncs", "error", err)
+		return
+	}
+
+	for i := range connections {
+		if err := s.queue(ctx, &connections[i], now); err != nil {
+			s.logger.Error("Failed to queue scheduled sync", "error", err, "connection_id", connections[i].ID)
+		}
+	}
+}
+
+func (s *Scheduler) queue(ctx context.Context, connection *models.IntegrationConnection, now time.Time) error {
+	next, err := NextSync(connection, now)
+	if err != nil {
+		return err
+	}
+
+	// Move the schedule on before queueing so a second API instance skips this run
+	claimed, err := s.db.ClaimScheduledSync(ctx, connection.ID, *connection.NextSyncAt, next)
+	if err != nil || !claimed {
+		return err
+	}
+
+	fromDate, toDate, err := syncWindow(connection, now)
+	if err != nil {
+		return err
+	}
+	if fromDate > toDate {
+		// Already synced through today
+		return nil
+	}
+
+	payload := BrokerImportPayload{
+		ConnectionID: &connection.ID,
+		FromDate:     fromDate,
+		ToDate:       toDate,
+		Scheduled:    true,
+	}
+
+	job, err := s.runner.Enqueue(ctx, connection.UserID, models.JobTypeBrokerImport, payload, nil)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Queued scheduled sync", "job_id", job.ID, "connection_id", connection.ID, "from", fromDate, "to", toDate)
+	return nil
+}
+
+// NextSync returns the first scheduled time for the connection after the given time
+func NextSync(connection *models.IntegrationConnection, after time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(connection.SyncTimezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid sync timezone %q", connection.SyncTimezone)
+	}
+	at, err := time.Parse(syncTimeLayout, connection.SyncTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid sync time %q, expected HH:MM", connection.SyncTime)
+	}
+
+	days := make(map[time.Weekday]bool, len(connection.SyncWeekdays))
+	for _, day := range connection.SyncWeekdays {
+		days[time.Weekday(day)] = true
+	}
+
+	local := after.In(loc)
+	for i := 0; i <= 7; i++ {
+		day := local.AddDate(0, 0, i)
+		candidate := time.Date(day.Year(), day.Month(), day.Day(), at.Hour(), at.Minute(), 0, 0, loc)
+		if days[candidate.Weekday()] && candidate.After(after) {
+			return candidate, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("sync schedule has no weekdays")
+}
+
+// syncWindow returns the days a scheduled sync should fetch: the day after the
+// high-water mark through today. A connection that has never synced starts today.
+func syncWindow(connection *models.IntegrationConnection, now time.Time) (string, string, error) {
+	loc, err := time.LoadLocation(connection.SyncTimezone)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid sync timezone %q", connection.SyncTimezone)
+	}
+
+	toDate := now.In(loc).Format(syncDateLayout)
+	fromDate := toDate
+	if connection.SyncedThrough != nil {
+		fromDate = connection.SyncedThrough.AddDate(0, 0, 1).Format(syncDateLayout)
+	}
+
+	return fromDate, toDate, nil
+}
+
+// retryDelay is the backoff before retrying after the given number of consecutive failures
+func retryDelay(failures int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < failures && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,218 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// Period is the length of the buckets daily results are rolled up into
+type Period string
+
+const (
+	PeriodDay   Period = "day"
+	PeriodWeek  Period = "week" // Monday to Sunday
+	PeriodMonth Period = "month"
+)
+
+// ParsePeriod validates a rollup period from a request, defaulting to days
+func ParsePeriod(value string) (Period, error) {
+	switch Period(value) {
+	case "", PeriodDay:
+		return PeriodDay, nil
+	case PeriodWeek, PeriodMonth:
+		return Period(value), nil
+	}
+	return "", fmt.Errorf("period must be %q, %q or %q", PeriodDay, PeriodWeek, PeriodMonth)
+}
+
+// PeriodPerformance is the result of the trades closed in one day, week or
+// month. Equity and drawdown are as of the period's close; for weeks and
+// months MaxDrawdown is the deepest daily drawdown within the period.
+type PeriodPerformance struct {
+	Date        string   `json:"date"` // first day of the period, YYYY-MM-DD
+	GrossPnL    float64  `json:"gross_pnl"`
+	NetPnL      float64  `json:"net_pnl"`
+	Fees        float64  `json:"fees"`
+	TradeCount  int      `json:"trade_count"`
+	WinCount    int      `json:"win_count"`
+	Equity      float64  `json:"equity"`   // starting equity plus cumulative net P&L
+	Drawdown    float64  `json:"drawdown"` // equity less its running peak, zero or negative
+	DrawdownPct *float64 `json:"drawdown_pct"`
+	MaxDrawdown float64  `json:"max_drawdown"`
+}
+
+// DrawdownStats describe the deepest peak-to-trough fall in equity. Duration
+// runs from the peak until equity regains it, or to the last day when it has
+// not; recovery runs from the trough and is nil until equity recovers.
+type DrawdownStats struct {
+	Value        float64  `json:"value"` // zero or negative
+	Percent      *float64 `json:"percent"`
+	PeakDate     string   `json:"peak_date,omitempty"`
+	TroughDate   string   `json:"trough_date,omitempty"`
+	RecoveryDate *string  `json:"recovery_date"`
+	DurationDays int      `json:"duration_days"`
+	RecoveryDays *int     `json:"recovery_days"`
+}
+
+// Performance is the calendar and equity curve of a selection of trades
+type Performance struct {
+	Period         Period              `json:"period"`
+	Timezone       string              `json:"timezone"`
+	StartingEquity float64             `json:"starting_equity"`
+	EndingEquity   float64             `json:"ending_equity"`
+	Periods        []PeriodPerformance `json:"periods"`
+	MaxDrawdown    DrawdownStats       `json:"max_drawdown"`
+}
+
+// DailyPerformance buckets the closed trades among trades by the day they
+// closed in loc, builds the equity curve from startingEquity and rolls the days
+// up into period. Drawdowns are always measured on the daily curve. Percentages
+// are of the running peak and are nil while the peak is not positive, as when
+// equity starts at zero.
+func DailyPerformance(trades []models.Trade, loc *time.Location, period Period, startingEquity float64) Performance {
+	days := make([]PeriodPerformance, 0)
+	index := make(map[string]int)
+
+	for _, trade := range trades {
+		if !IsClosed(trade) {
+			continue
+		}
+		key := trade.ClosedAt.In(loc).Format("2006-01-02")
+		i, ok := index[key]
+		if !ok {
+			i = len(days)
+			index[key] = i
+			days = append(days, PeriodPerformance{Date: key})
+		}
+
+		pnl := *trade.PnL
+		day := &days[i]
+		day.NetPnL += pnl
+		day.GrossPnL += pnl + trade.Fees
+		day.Fees += trade.Fees
+		day.TradeCount++
+		if pnl > 0 {
+			day.WinCount++
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+
+	performance := Performance{
+		Period:         period,
+		Timezone:       loc.String(),
+		StartingEquity: startingEquity,
+		EndingEquity:   startingEquity,
+		Periods:        days,
+	}
+
+	// Walk the equity curve, tracking the running peak and the deepest fall from it
+	equity, peak := startingEquity, startingEquity
+	peakDate := ""
+	var deepest *DrawdownStats
+	var deepestPeak float64
+	for i := range days {
+		day := &days[i]
+		equity += day.NetPnL
+		if equity >= peak {
+			if deepest != nil && deepest.RecoveryDate == nil && equity >= deepestPeak {
+				date := day.Date
+				deepest.RecoveryDate = &date
+			}
+			peak, peakDate = equity, day.Date
+		}
+
+		day.Equity = equity
+		day.Drawdown = equity - peak
+		day.MaxDrawdown = day.Drawdown
+		day.DrawdownPct = percentOf(day.Drawdown, peak)
+
+		if day.Drawdown < 0 && (deepest == nil || day.Drawdown < deepest.Value) {
+			deepest = &DrawdownStats{
+				Value:      day.Drawdown,
+				Percent:    day.DrawdownPct,
+				PeakDate:   peakDate,
+				TroughDate: day.Date,
+			}
+			deepestPeak = peak
+		}
+	}
+	performance.EndingEquity = equity
+
+	if deepest != nil {
+		if deepest.PeakDate == "" {
+			// The curve never rose above its start, so the drawdown runs from the first day
+			deepest.PeakDate = days[0].Date
+		}
+		end := days[len(days)-1].Date
+		if deepest.RecoveryDate != nil {
+			end = *deepest.RecoveryDate
+			recovery := daysBetween(deepest.TroughDate, end)
+			deepest.RecoveryDays = &recovery
+		}
+		deepest.DurationDays = daysBetween(deepest.PeakDate, end)
+		performance.MaxDrawdown = *deepest
+	}
+
+	if period != PeriodDay {
+		performance.Periods = rollUp(days, period)
+	}
+
+	return performance
+}
+
+// rollUp combines daily results into weeks or months
+func rollUp(days []PeriodPerformance, period Period) []PeriodPerformance {
+	rolled := make([]PeriodPerformance, 0)
+	for _, day := range days {
+		start := periodStart(day.Date, period)
+		if len(rolled) == 0 || rolled[len(rolled)-1].Date != start {
+			rolled = append(rolled, PeriodPerformance{Date: start})
+		}
+
+		p := &rolled[len(rolled)-1]
+		p.GrossPnL += day.GrossPnL
+		p.NetPnL += day.NetPnL
+		p.Fees += day.Fees
+		p.TradeCount += day.TradeCount
+		p.WinCount += day.WinCount
+		p.Equity = day.Equity
+		p.Drawdown = day.Drawdown
+		p.DrawdownPct = day.DrawdownPct
+		if day.Drawdown < p.MaxDrawdown {
+			p.MaxDrawdown = day.Drawdown
+		}
+	}
+	return rolled
+}
+
+// periodStart returns the first day of the week or month a YYYY-MM-DD date falls in
+func periodStart(date string, period Period) string {
+	t, _ := time.Parse("2006-01-02", date)
+	switch period {
+	case PeriodWeek:
+		offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+		t = t.AddDate(0, 0, -offset)
+	case PeriodMonth:
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return t.Format("2006-01-02")
+}
+
+// daysBetween counts the calendar days from one YYYY-MM-DD date to another
+func daysBetween(from, to string) int {
+	start, _ := time.Parse("2006-01-02", from)
+	end, _ := time.Parse("2006-01-02", to)
+	return int(end.Sub(start).Hours() / 24)
+}
+
+// percentOf returns value as a percentage of base, or nil when base is not positive
+func percentOf(value, base float64) *float64 {
+	if base <= 0 {
+		return nil
+	}
+	pct := value / base * 100
+	return &pct
+}
@@ -0,0 +1,254 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// tradeClosedAt builds a closed trade with a net P&L and fees, closed at an RFC 3339 time
+func tradeClosedAt(t *testing.T, closed string, pnl, fees float64) models.Trade {
+	t.Helper()
+	closedAt, err := time.Parse(time.RFC3339, closed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.Trade{
+		TradeType: models.TradeLong,
+		Fees:      fees,
+		PnL:       &pnl,
+		OpenedAt:  closedAt.Add(-time.Hour),
+		ClosedAt:  &closedAt,
+	}
+}
+
+func newYork(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	return loc
+}
+
+func periodDates(periods []PeriodPerformance) []string {
+	dates := make([]string, 0, len(periods))
+	for _, p := range periods {
+		dates = append(dates, p.Date)
+	}
+	return dates
+}
+
+func sameDates(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDailyPerformanceBucketsDaysAcrossDST(t *testing.T) {
+	loc := newYork(t)
+
+	tests := []struct {
+		name   string
+		closed string
+		want   string
+	}{
+		// 23:30 EST, the evening before clocks go forward
+		{"before spring forward", "2024-03-10T04:30:00Z", "2024-03-09"},
+		// 00:30 EDT; a fixed UTC-5 offset would put it on the 10th
+		{"after spring forward", "2024-03-11T04:30:00Z", "2024-03-11"},
+		// 23:30 EST, the evening clocks went back; a fixed UTC-4 offset would put it on the 4th
+		{"after fall back", "2024-11-04T04:30:00Z", "2024-11-03"},
+		// 00:30 EDT, the night clocks go back
+		{"before fall back", "2024-11-03T04:30:00Z", "2024-11-03"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			performance := DailyPerformance([]models.Trade{tradeClosedAt(t, tt.closed, 10, 0)}, loc, PeriodDay, 0)
+			if got := periodDates(performance.Periods); !sameDates(got, []string{tt.want}) {
+				t.Errorf("days = %v, want [%s]", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDailyPerformanceTotalsAndDrawdown(t *testing.T) {
+	loc := newYork(t)
+	trades := []models.Trade{
+		tradeClosedAt(t, "2024-03-04T15:00:00Z", 100, 1),
+		// 21:00 New York time on the 4th, the 5th in UTC
+		tradeClosedAt(t, "2024-03-05T02:00:00Z", -300, 1),
+		tradeClosedAt(t, "2024-03-06T15:00:00Z", -100, 1),
+		tradeClosedAt(t, "2024-03-12T15:00:00Z", 500, 1),
+		{TradeType: models.TradeLong, OpenedAt: time.Date(2024, 3, 12, 14, 0, 0, 0, time.UTC)},
+	}
+
+	performance := DailyPerformance(trades, loc, PeriodDay, 1000)
+
+	if got := periodDates(performance.Periods); !sameDates(got, []string{"2024-03-04", "2024-03-06", "2024-03-12"}) {
+		t.Fatalf("days = %v, want [2024-03-04 2024-03-06 2024-03-12]", got)
+	}
+	if performance.Timezone != "America/New_York" || performance.EndingEquity != 1200 {
+		t.Errorf("timezone, ending equity = %s, %v; want America/New_York, 1200", performance.Timezone, performance.EndingEquity)
+	}
+
+	first := performance.Periods[0]
+	if first.TradeCount != 2 || first.WinCount != 1 || !approx(first.NetPnL, -200) ||
+		!approx(first.GrossPnL, -198) || !approx(first.Fees, 2) || !approx(first.Equity, 800) {
+		t.Errorf("first day = %+v, want 2 trades, 1 win, -200 net, -198 gross, 2 fees and 800 equity", first)
+	}
+
+	wantDrawdowns := []float64{-200, -300, 0}
+	wantPcts := []float64{-20, -30, 0}
+	for i, p := range performance.Periods {
+		if !approx(p.Drawdown, wantDrawdowns[i]) || p.DrawdownPct == nil || !approx(*p.DrawdownPct, wantPcts[i]) {
+			t.Errorf("%s drawdown = %v (%v%%), want %v (%v%%)", p.Date, p.Drawdown, fmtRatio(p.DrawdownPct), wantDrawdowns[i], wantPcts[i])
+		}
+	}
+
+	dd := performance.MaxDrawdown
+	if !approx(dd.Value, -300) || !sameRatio(dd.Percent, ptr(-30)) {
+		t.Errorf("max drawdown = %v (%v%%), want -300 (-30%%)", dd.Value, fmtRatio(dd.Percent))
+	}
+	if dd.PeakDate != "2024-03-04" || dd.TroughDate != "2024-03-06" {
+		t.Errorf("peak, trough = %s, %s; want 2024-03-04, 2024-03-06", dd.PeakDate, dd.TroughDate)
+	}
+	if dd.RecoveryDate == nil || *dd.RecoveryDate != "2024-03-12" || dd.RecoveryDays == nil || *dd.RecoveryDays != 6 {
+		t.Errorf("recovery = %v after %v days, want 2024-03-12 after 6", dd.RecoveryDate, dd.RecoveryDays)
+	}
+	if dd.DurationDays != 8 {
+		t.Errorf("duration = %d days, want 8", dd.DurationDays)
+	}
+}
+
+func TestDailyPerformanceUnrecoveredDrawdown(t *testing.T) {
+	trades := []models.Trade{
+		tradeClosedAt(t, "2024-05-01T15:00:00Z", 500, 0),
+		tradeClosedAt(t, "2024-05-03T15:00:00Z", -200, 0),
+		tradeClosedAt(t, "2024-05-10T15:00:00Z", 100, 0),
+	}
+
+	dd := DailyPerformance(trades, time.UTC, PeriodDay, 0).MaxDrawdown
+
+	if !approx(dd.Value, -200) || dd.PeakDate != "2024-05-01" || dd.TroughDate != "2024-05-03" {
+		t.Errorf("max drawdown = %v from %s to %s, want -200 from 2024-05-01 to 2024-05-03", dd.Value, dd.PeakDate, dd.TroughDate)
+	}
+	if !sameRatio(dd.Percent, ptr(-40)) {
+		t.Errorf("max drawdown percent = %v, want -40", fmtRatio(dd.Percent))
+	}
+	if dd.RecoveryDate != nil || dd.RecoveryDays != nil {
+		t.Errorf("recovery = %v after %v days, want none", dd.RecoveryDate, dd.RecoveryDays)
+	}
+	// Still in drawdown, so the duration runs to the last day
+	if dd.DurationDays != 9 {
+		t.Errorf("duration = %d days, want 9", dd.DurationDays)
+	}
+}
+
+func TestDailyPerformanceWithoutPositiveEquity(t *testing.T) {
+	trades := []models.Trade{
+		tradeClosedAt(t, "2024-05-01T15:00:00Z", -50, 0),
+		tradeClosedAt(t, "2024-05-02T15:00:00Z", -25, 0),
+	}
+
+	performance := DailyPerformance(trades, time.UTC, PeriodDay, 0)
+
+	for _, p := range performance.Periods {
+		if p.DrawdownPct != nil {
+			t.Errorf("%s drawdown percent = %v, want nil while equity has no positive peak", p.Date, *p.DrawdownPct)
+		}
+	}
+	dd := performance.MaxDrawdown
+	if !approx(dd.Value, -75) || dd.Percent != nil || dd.PeakDate != "2024-05-01" || dd.DurationDays != 1 {
+		t.Errorf("max drawdown = %+v, want -75 with no percent, from 2024-05-01 over 1 day", dd)
+	}
+}
+
+func TestDailyPerformanceRollups(t *testing.T) {
+	trades := []models.Trade{
+		tradeClosedAt(t, "2024-01-29T15:00:00Z", 100, 1), // Monday
+		tradeClosedAt(t, "2024-01-31T15:00:00Z", -150, 1),
+		tradeClosedAt(t, "2024-02-01T15:00:00Z", 20, 1),
+		tradeClosedAt(t, "2024-02-04T15:00:00Z", 40, 1), // Sunday
+		tradeClosedAt(t, "2024-02-05T15:00:00Z", 200, 1),
+	}
+
+	tests := []struct {
+		period       Period
+		dates        []string
+		netPnL       []float64
+		tradeCounts  []int
+		equity       []float64
+		maxDrawdowns []float64
+	}{
+		{
+			period:       PeriodWeek,
+			dates:        []string{"2024-01-29", "2024-02-05"},
+			netPnL:       []float64{10, 200},
+			tradeCounts:  []int{4, 1},
+			equity:       []float64{1010, 1210},
+			maxDrawdowns: []float64{-150, 0},
+		},
+		{
+			period:       PeriodMonth,
+			dates:        []string{"2024-01-01", "2024-02-01"},
+			netPnL:       []float64{-50, 260},
+			tradeCounts:  []int{2, 3},
+			equity:       []float64{950, 1210},
+			maxDrawdowns: []float64{-150, -130},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.period), func(t *testing.T) {
+			performance := DailyPerformance(trades, time.UTC, tt.period, 1000)
+
+			if performance.Period != tt.period {
+				t.Errorf("period = %s, want %s", performance.Period, tt.period)
+			}
+			if got := periodDates(performance.Periods); !sameDates(got, tt.dates) {
+				t.Fatalf("periods = %v, want %v", got, tt.dates)
+			}
+			for i, p := range performance.Periods {
+				if !approx(p.NetPnL, tt.netPnL[i]) || p.TradeCount != tt.tradeCounts[i] ||
+					!approx(p.Equity, tt.equity[i]) || !approx(p.MaxDrawdown, tt.maxDrawdowns[i]) {
+					t.Errorf("%s = %v net, %d trades, %v equity, %v max drawdown; want %v, %d, %v, %v",
+						p.Date, p.NetPnL, p.TradeCount, p.Equity, p.MaxDrawdown,
+						tt.netPnL[i], tt.tradeCounts[i], tt.equity[i], tt.maxDrawdowns[i])
+				}
+			}
+
+			// Drawdowns are measured on the daily curve whatever the period
+			if dd := performance.MaxDrawdown; !approx(dd.Value, -150) || dd.TroughDate != "2024-01-31" {
+				t.Errorf("max drawdown = %v on %s, want -150 on 2024-01-31", dd.Value, dd.TroughDate)
+			}
+		})
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := map[string]Period{
+		"":      PeriodDay,
+		"day":   PeriodDay,
+		"week":  PeriodWeek,
+		"month": PeriodMonth,
+	}
+	for value, want := range tests {
+		got, err := ParsePeriod(value)
+		if err != nil || got != want {
+			t.Errorf("ParsePeriod(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+
+	if _, err := ParsePeriod("year"); err == nil {
+		t.Error("ParsePeriod(\"year\") succeeded, want an error")
+	}
+}
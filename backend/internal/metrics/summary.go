@@ -0,0 +1,141 @@
+// Package metrics computes trading performance statistics from closed trades.
+package metrics
+
+import (
+	"math"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// Stats are the performance statistics of a set of closed trades. P&L is net of
+// fees unless named gross. Ratios with nothing to divide by, such as the profit
+// factor of a set without losing trades, are nil.
+type Stats struct {
+	TradeCount         int      `json:"trade_count"`
+	WinCount           int      `json:"win_count"`
+	LossCount          int      `json:"loss_count"`
+	BreakevenCount     int      `json:"breakeven_count"`
+	WinRate            float64  `json:"win_rate"` // percent of trades with a positive net P&L
+	NetPnL             float64  `json:"net_pnl"`
+	GrossPnL           float64  `json:"gross_pnl"` // before fees
+	TotalFees          float64  `json:"total_fees"`
+	WinningPnL         float64  `json:"winning_pnl"` // sum of the winning trades
+	LosingPnL          float64  `json:"losing_pnl"`  // sum of the losing trades, negative
+	ProfitFactor       *float64 `json:"profit_factor"`
+	Expectancy         float64  `json:"expectancy"` // average net P&L per trade
+	AverageWin         float64  `json:"average_win"`
+	AverageLoss        float64  `json:"average_loss"` // negative
+	LargestWin         float64  `json:"largest_win"`
+	LargestLoss        float64  `json:"largest_loss"` // negative
+	PayoffRatio        *float64 `json:"payoff_ratio"` // average win over the size of the average loss
+	AverageHoldSeconds float64  `json:"average_hold_seconds"`
+}
+
+// Summary is the statistics of a selection of trades, overall and by direction
+type Summary struct {
+	Stats
+	Long           Stats `json:"long"`
+	Short          Stats `json:"short"`
+	OpenTradeCount int   `json:"open_trade_count"` // matching trades left out because they are still open
+}
+
+// Summarize computes the statistics of the closed trades among trades
+func Summarize(trades []models.Trade) Summary {
+	var all, long, short accumulator
+	var open int
+
+	for _, trade := range trades {
+		if !IsClosed(trade) {
+			open++
+			continue
+		}
+		all.add(trade)
+		if trade.TradeType == models.TradeShort {
+			short.add(trade)
+		} else {
+			long.add(trade)
+		}
+	}
+
+	return Summary{
+		Stats:          all.stats(),
+		Long:           long.stats(),
+		Short:          short.stats(),
+		OpenTradeCount: open,
+	}
+}
+
+// IsClosed reports whether a trade has a realized result
+func IsClosed(trade models.Trade) bool {
+	return trade.PnL != nil && trade.ClosedAt != nil
+}
+
+type accumulator struct {
+	count, wins, losses     int
+	net, fees               float64
+	winning, losing         float64
+	largestWin, largestLoss float64
+	holdSeconds             float64
+}
+
+func (a *accumulator) add(trade models.Trade) {
+	pnl := *trade.PnL
+
+	a.count++
+	a.net += pnl
+	a.fees += trade.Fees
+	a.holdSeconds += trade.ClosedAt.Sub(trade.OpenedAt).Seconds()
+
+	switch {
+	case pnl > 0:
+		a.wins++
+		a.winning += pnl
+		a.largestWin = math.Max(a.largestWin, pnl)
+	case pnl < 0:
+		a.losses++
+		a.losing += pnl
+		a.largestLoss = math.Min(a.largestLoss, pnl)
+	}
+}
+
+func (a *accumulator) stats() Stats {
+	s := Stats{
+		TradeCount:     a.count,
+		WinCount:       a.wins,
+		LossCount:      a.losses,
+		BreakevenCount: a.count - a.wins - a.losses,
+		NetPnL:         a.net,
+		GrossPnL:       a.net + a.fees,
+		TotalFees:      a.fees,
+		WinningPnL:     a.winning,
+		LosingPnL:      a.losing,
+		LargestWin:     a.largestWin,
+		LargestLoss:    a.largestLoss,
+	}
+	if a.count == 0 {
+		return s
+	}
+
+	s.WinRate = float64(a.wins) / float64(a.count) * 100
+	s.Expectancy = a.net / float64(a.count)
+	s.AverageHoldSeconds = a.holdSeconds / float64(a.count)
+	if a.wins > 0 {
+		s.AverageWin = a.winning / float64(a.wins)
+	}
+	if a.losses > 0 {
+		s.AverageLoss = a.losing / float64(a.losses)
+		s.ProfitFactor = ratio(a.winning, -a.losing)
+		s.PayoffRatio = ratio(s.AverageWin, -s.AverageLoss)
+	}
+
+	return s
+}
+
+// ratio divides a by b, or returns nil when b is zero
+func ratio(a, b float64) *float64 {
+	if b == 0 {
+		return nil
+	}
+	r := a / b
+	return &r
+}
@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+var testOpen = time.Date(2025, 11, 10, 14, 30, 0, 0, time.UTC)
+
+// closedTrade builds a trade with a net P&L and fees, closed after hold
+func closedTrade(tradeType models.TradeType, pnl, fees float64, hold time.Duration) models.Trade {
+	closedAt := testOpen.Add(hold)
+	return models.Trade{
+		TradeType: tradeType,
+		Fees:      fees,
+		PnL:       &pnl,
+		OpenedAt:  testOpen,
+		ClosedAt:  &closedAt,
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// sameRatio compares optional ratios, which are nil when there is nothing to divide by
+func sameRatio(got, want *float64) bool {
+	if got == nil || want == nil {
+		return got == nil && want == nil
+	}
+	return approx(*got, *want)
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+func TestSummarize(t *testing.T) {
+	long, short := models.TradeLong, models.TradeShort
+
+	tests := []struct {
+		name   string
+		trades []models.Trade
+		want   Stats
+		open   int
+	}{
+		{
+			name:   "no trades",
+			trades: nil,
+			want:   Stats{},
+		},
+		{
+			name: "no losses",
+			trades: []models.Trade{
+				closedTrade(long, 100, 2, time.Minute),
+				closedTrade(short, 50, 1, 3*time.Minute),
+			},
+			want: Stats{
+				TradeCount: 2, WinCount: 2, WinRate: 100,
+				NetPnL: 150, GrossPnL: 153, TotalFees: 3, WinningPnL: 150,
+				Expectancy: 75, AverageWin: 75, LargestWin: 100,
+				AverageHoldSeconds: 120,
+			},
+		},
+		{
+			name: "no wins",
+			trades: []models.Trade{
+				closedTrade(long, -40, 1, time.Minute),
+				closedTrade(long, -60, 1, time.Minute),
+			},
+			want: Stats{
+				TradeCount: 2, LossCount: 2,
+				NetPnL: -100, GrossPnL: -98, TotalFees: 2, LosingPnL: -100,
+				ProfitFactor: ptr(0), Expectancy: -50, AverageLoss: -50, LargestLoss: -60,
+				PayoffRatio: ptr(0), AverageHoldSeconds: 60,
+			},
+		},
+		{
+			name: "breakeven and open trades",
+			trades: []models.Trade{
+				closedTrade(long, 100, 0, time.Minute),
+				closedTrade(short, -50, 0, time.Minute),
+				closedTrade(long, 0, 0, time.Minute),
+				{TradeType: long, OpenedAt: testOpen},
+			},
+			want: Stats{
+				TradeCount: 3, WinCount: 1, LossCount: 1, BreakevenCount: 1, WinRate: 100.0 / 3,
+				NetPnL: 50, GrossPnL: 50, WinningPnL: 100, LosingPnL: -50,
+				ProfitFactor: ptr(2), Expectancy: 50.0 / 3, AverageWin: 100, AverageLoss: -50,
+				LargestWin: 100, LargestLoss: -50, PayoffRatio: ptr(2), AverageHoldSeconds: 60,
+			},
+			open: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := Summarize(tt.trades)
+			assertStats(t, summary.Stats, tt.want)
+			if summary.OpenTradeCount != tt.open {
+				t.Errorf("OpenTradeCount = %d, want %d", summary.OpenTradeCount, tt.open)
+			}
+		})
+	}
+}
+
+func TestSummarizeSplitsByDirection(t *testing.T) {
+	summary := Summarize([]models.Trade{
+		closedTrade(models.TradeLong, 120, 1, time.Minute),
+		closedTrade(models.TradeLong, -20, 1, time.Minute),
+		closedTrade(models.TradeShort, -30, 1, time.Minute),
+	})
+
+	if summary.TradeCount != 3 || !approx(summary.NetPnL, 70) {
+		t.Errorf("overall = %d trades, %v net; want 3 trades, 70 net", summary.TradeCount, summary.NetPnL)
+	}
+	if summary.Long.TradeCount != 2 || !approx(summary.Long.NetPnL, 100) || !sameRatio(summary.Long.ProfitFactor, ptr(6)) {
+		t.Errorf("long = %d trades, %v net; want 2 trades, 100 net and a profit factor of 6", summary.Long.TradeCount, summary.Long.NetPnL)
+	}
+	if summary.Short.TradeCount != 1 || !approx(summary.Short.NetPnL, -30) || summary.Short.WinRate != 0 {
+		t.Errorf("short = %d trades, %v net; want 1 trade, -30 net", summary.Short.TradeCount, summary.Short.NetPnL)
+	}
+}
+
+func assertStats(t *testing.T, got, want Stats) {
+	t.Helper()
+
+	if got.TradeCount != want.TradeCount || got.WinCount != want.WinCount ||
+		got.LossCount != want.LossCount || got.BreakevenCount != want.BreakevenCount {
+		t.Errorf("trades/wins/losses/breakeven = %d/%d/%d/%d, want %d/%d/%d/%d",
+			got.TradeCount, got.WinCount, got.LossCount, got.BreakevenCount,
+			want.TradeCount, want.WinCount, want.LossCount, want.BreakevenCount)
+	}
+
+	values := map[string][2]float64{
+		"WinRate":            {got.WinRate, want.WinRate},
+		"NetPnL":             {got.NetPnL, want.NetPnL},
+		"GrossPnL":           {got.GrossPnL, want.GrossPnL},
+		"TotalFees":          {got.TotalFees, want.TotalFees},
+		"WinningPnL":         {got.WinningPnL, want.WinningPnL},
+		"LosingPnL":          {got.LosingPnL, want.LosingPnL},
+		"Expectancy":         {got.Expectancy, want.Expectancy},
+		"AverageWin":         {got.AverageWin, want.AverageWin},
+		"AverageLoss":        {got.AverageLoss, want.AverageLoss},
+		"LargestWin":         {got.LargestWin, want.LargestWin},
+		"LargestLoss":        {got.LargestLoss, want.LargestLoss},
+		"AverageHoldSeconds": {got.AverageHoldSeconds, want.AverageHoldSeconds},
+	}
+	for name, v := range values {
+		if !approx(v[0], v[1]) {
+			t.Errorf("%s = %v, want %v", name, v[0], v[1])
+		}
+	}
+
+	if !sameRatio(got.ProfitFactor, want.ProfitFactor) {
+		t.Errorf("ProfitFactor = %v, want %v", fmtRatio(got.ProfitFactor), fmtRatio(want.ProfitFactor))
+	}
+	if !sameRatio(got.PayoffRatio, want.PayoffRatio) {
+		t.Errorf("PayoffRatio = %v, want %v", fmtRatio(got.PayoffRatio), fmtRatio(want.PayoffRatio))
+	}
+}
+
+func fmtRatio(r *float64) interface{} {
+	if r == nil {
+		return "nil"
+	}
+	return *r
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccountType string
+
+const (
+	AccountCash   AccountType = "CASH"
+	AccountMargin AccountType = "MARGIN"
+	AccountIRA    AccountType = "IRA"
+	AccountProp   AccountType = "PROP"
+	AccountOther  AccountType = "OTHER"
+)
+
+// Valid reports whether the account type is one of the known types
+func (t AccountType) Valid() bool {
+	switch t {
+	case AccountCash, AccountMargin, AccountIRA, AccountProp, AccountOther:
+		return true
+	}
+	return false
+}
+
+// Account is a brokerage or prop firm account that trades are booked to
+type Account struct {
+	ID              uuid.UUID   `json:"id"`
+	UserID          uuid.UUID   `json:"user_id"`
+	Name            string      `json:"name"`
+	Broker          string      `json:"broker,omitempty"`
+	AccountNumber   string      `json:"account_number,omitempty"` // as reported by the broker; matched against imported executions
+	Currency        string      `json:"currency"`
+	AccountType     AccountType `json:"account_type"`
+	StartingBalance float64     `json:"starting_balance"`
+	IsActive        bool        `json:"is_active"`
+	ImportBatchID   *uuid.UUID  `json:"import_batch_id,omitempty"` // the import that created the account, if any
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
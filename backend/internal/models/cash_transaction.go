@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CashTransactionType string
+
+const (
+	CashDeposit    CashTransactionType = "DEPOSIT"
+	CashWithdrawal CashTransactionType = "WITHDRAWAL"
+	CashFee        CashTransactionType = "FEE"
+	CashInterest   CashTransactionType = "INTEREST"
+	CashDividend   CashTransactionType = "DIVIDEND"
+	CashPayout     CashTransactionType = "PAYOUT" // profit paid out of a prop firm account
+	CashAdjustment CashTransactionType = "ADJUSTMENT"
+	CashOther      CashTransactionType = "OTHER"
+)
+
+// Valid reports whether the transaction type is one of the known types
+func (t CashTransactionType) Valid() bool {
+	switch t {
+	case CashDeposit, CashWithdrawal, CashFee, CashInterest, CashDividend, CashPayout, CashAdjustment, CashOther:
+		return true
+	}
+	return false
+}
+
+// IsFunding reports whether the type moves the trader's own money in or out of
+// the account, as opposed to income or costs that count towards its return
+func (t CashTransactionType) IsFunding() bool {
+	switch t {
+	case CashDeposit, CashWithdrawal, CashPayout, CashAdjustment:
+		return true
+	}
+	return false
+}
+
+// CashTransaction is an entry in an account's cash ledger
+type CashTransaction struct {
+	ID            uuid.UUID           `json:"id"`
+	UserID        uuid.UUID           `json:"user_id"`
+	AccountID     uuid.UUID           `json:"account_id"`
+	Type          CashTransactionType `json:"type"`
+	Amount        float64             `json:"amount"` // positive adds to the account, negative takes from it
+	Description   string              `json:"description,omitempty"`
+	Reference     string              `json:"reference,omitempty"`
+	OccurredAt    time.Time           `json:"occurred_at"`
+	Fingerprint   string              `json:"fingerprint,omitempty"`
+	ImportBatchID *uuid.UUID          `json:"import_batch_id,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+
+	// Account is the broker account number an imported entry was reported
+	// under, used to find its account when none was chosen
+	Account string `json:"-"`
+}
+
+// AccountBalance is one day of an account's balance series. Funding is the
+// money deposited or withdrawn that day; CashIncome the fees, interest and
+// other ledger entries that count towards the return along with RealizedPnL.
+type AccountBalance struct {
+	Date           string  `json:"date"`
+	OpeningBalance float64 `json:"opening_balance"`
+	Funding        float64 `json:"funding"`
+	CashIncome     float64 `json:"cash_income"`
+	RealizedPnL    float64 `json:"realized_pnl"`
+	Balance        float64 `json:"balance"`
+	ReturnPct      float64 `json:"return_pct"` // (cash income + realized P&L) / opening balance
+}
+
+// AccountBalanceSeries is an account's daily balance between two dates
+type AccountBalanceSeries struct {
+	AccountID       uuid.UUID        `json:"account_id"`
+	Currency        string           `json:"currency"`
+	OpeningBalance  float64          `json:"opening_balance"` // balance before the first day
+	EndingBalance   float64          `json:"ending_balance"`
+	TotalFunding    float64          `json:"total_funding"`
+	TotalCashIncome float64          `json:"total_cash_income"`
+	TotalRealized   float64          `json:"total_realized_pnl"`
+	ReturnPct       float64          `json:"return_pct"` // daily returns compounded, so funding does not count as performance
+	Days            []AccountBalance `json:"days"`
+}
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExecutionSide string
+
+const (
+	SideBuy  ExecutionSide = "BUY"
+	SideSell ExecutionSide = "SELL"
+)
+
+// Execution is a single fill reported by a broker. A trade is the sum of its executions.
+type Execution struct {
+	ID            uuid.UUID     `json:"id"`
+	TradeID       uuid.UUID     `json:"trade_id"`
+	UserID        uuid.UUID     `json:"user_id"`
+	Account       string        `json:"account,omitempty"`
+	Symbol        string        `json:"symbol"`
+	Side          ExecutionSide `json:"side"`
+	Quantity      float64       `json:"quantity"`
+	Price         float64       `json:"price"`
+	ExecutedAt    time.Time     `json:"executed_at"`
+	Route         string        `json:"route,omitempty"`
+	Liquidity     string        `json:"liquidity,omitempty"`
+	OrderID       string        `json:"order_id,omitempty"`
+	FillID        string        `json:"fill_id,omitempty"`
+	Commission    float64       `json:"commission"`
+	ECNFee        float64       `json:"ecn_fee"`
+	SECFee        float64       `json:"sec_fee"`
+	TAFFee        float64       `json:"taf_fee"`
+	NSCCFee       float64       `json:"nscc_fee"`
+	ClearingFee   float64       `json:"clearing_fee"`
+	MiscFee       float64       `json:"misc_fee"`
+	Multiplier    float64       `json:"multiplier,omitempty"`    // contract multiplier; 1 when unset
+	RealizedPnL   *float64      `json:"realized_pnl,omitempty"`  // realized P&L as reported by the broker
+	ForeignQuote  bool          `json:"foreign_quote,omitempty"` // priced in a currency other than the account's
+	Fingerprint   string        `json:"fingerprint,omitempty"`
+	ImportBatchID *uuid.UUID    `json:"import_batch_id,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// TotalFees returns the sum of every fee charged on the execution
+func (e Execution) TotalFees() float64 {
+	return e.Commission + e.ECNFee + e.SECFee + e.TAFFee + e.NSCCFee + e.ClearingFee + e.MiscFee
+}
+
+// ContractMultiplier returns the execution's multiplier, treating an unset one as 1
+func (e Execution) ContractMultiplier() float64 {
+	if e.Multiplier == 0 {
+		return 1
+	}
+	return e.Multiplier
+}
+
+// FeeBreakdown is the total of each kind of fee across a set of executions
+type FeeBreakdown struct {
+	Commission  float64 `json:"commission"`
+	ECNFee      float64 `json:"ecn_fee"`
+	SECFee      float64 `json:"sec_fee"`
+	TAFFee      float64 `json:"taf_fee"`
+	NSCCFee     float64 `json:"nscc_fee"`
+	ClearingFee float64 `json:"clearing_fee"`
+	MiscFee     float64 `json:"misc_fee"`
+	Total       float64 `json:"total"`
+}
+
+// SumFees itemizes the fees charged on the executions
+func SumFees(executions []Execution) FeeBreakdown {
+	var fees FeeBreakdown
+	for _, e := range executions {
+		fees.Commission += e.Commission
+		fees.ECNFee += e.ECNFee
+		fees.SECFee += e.SECFee
+		fees.TAFFee += e.TAFFee
+		fees.NSCCFee += e.NSCCFee
+		fees.ClearingFee += e.ClearingFee
+		fees.MiscFee += e.MiscFee
+		fees.Total += e.TotalFees()
+	}
+	return fees
+}
+
+// RebuildFromExecutions recalculates the trade's aggregate fields from its executions.
+// The side of the first execution decides the direction; executions on the opposite
+// side are exits. The trade is only closed once the exits cover the full entry quantity.
+// BrokerPnL is the sum of the realized P&L the broker reported on the executions, if any.
+// Trades priced in a currency other than the account's take that as their P&L,
+// since their price differences are not in the account currency.
+func (t *Trade) RebuildFromExecutions() {
+	if len(t.Executions) == 0 {
+		return
+	}
+
+	sort.SliceStable(t.Executions, func(i, j int) bool {
+		return t.Executions[i].ExecutedAt.Before(t.Executions[j].ExecutedAt)
+	})
+
+	first := t.Executions[0]
+	last := t.Executions[len(t.Executions)-1]
+
+	t.Symbol = first.Symbol
+	t.Multiplier = first.ContractMultiplier()
+	t.PnLFromBroker = first.ForeignQuote
+	if first.Side == SideBuy {
+		t.TradeType = TradeLong
+	} else {
+		t.TradeType = TradeShort
+	}
+
+	var entryQty, entryValue, exitQty, exitValue, fees float64
+	var brokerPnL *float64
+	for _, e := range t.Executions {
+		if e.Side == first.Side {
+			entryQty += e.Quantity
+			entryValue += e.Quantity * e.Price
+		} else {
+			exitQty += e.Quantity
+			exitValue += e.Quantity * e.Price
+		}
+		fees += e.TotalFees()
+		if e.RealizedPnL != nil {
+			if brokerPnL == nil {
+				brokerPnL = new(float64)
+			}
+			*brokerPnL += *e.RealizedPnL
+		}
+	}
+
+	t.Quantity = entryQty
+	t.EntryPrice = entryValue / entryQty
+	t.Fees = fees
+	t.OpenedAt = first.ExecutedAt
+	t.ExitPrice = nil
+	t.ClosedAt = nil
+	t.PnL = nil
+	t.BrokerPnL = brokerPnL
+
+	// Allow for float rounding when comparing quantities
+	if exitQty > 0 && exitQty >= entryQty-1e-9 {
+		exitPrice := exitValue / exitQty
+		closedAt := last.ExecutedAt
+
+		var pnl float64
+		if t.TradeType == TradeLong {
+			pnl = (exitPrice-t.EntryPrice)*t.Quantity*t.Multiplier - fees
+		} else {
+			pnl = (t.EntryPrice-exitPrice)*t.Quantity*t.Multiplier - fees
+		}
+
+		t.ExitPrice = &exitPrice
+		t.ClosedAt = &closedAt
+		t.PnL = &pnl
+		if t.PnLFromBroker {
+			t.PnL = nil
+			if brokerPnL != nil {
+				pnl = *brokerPnL
+				t.PnL = &pnl
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ComputeFingerprint returns a stable identity for the execution: the broker's
+// fill id when there is one, otherwise a hash of account, symbol, time, side,
+// quantity and price
+func (e Execution) ComputeFingerprint() string {
+	if e.FillID != "" {
+		return hashParts("fill", e.Account, e.Symbol, e.FillID)
+	}
+
+	return hashParts(
+		"exec",
+		e.Account,
+		strings.ToUpper(e.Symbol),
+		e.ExecutedAt.UTC().Format(time.RFC3339Nano),
+		string(e.Side),
+		formatNumber(e.Quantity),
+		formatNumber(e.Price),
+	)
+}
+
+// FingerprintExecutions fills in the fingerprints of executions that have none.
+// Identical fills without a fill id, such as two partial fills of the same size
+// and price in the same second, are told apart by their occurrence in the
+// order given: the first keeps the plain fingerprint and each repeat adds its
+// index, so a file re-imported in the same order yields the same fingerprints.
+func FingerprintExecutions(executions []Execution) {
+	seen := make(map[string]int)
+	for i := range executions {
+		e := &executions[i]
+		if e.Fingerprint != "" {
+			continue
+		}
+
+		base := e.ComputeFingerprint()
+		e.Fingerprint = base
+		if e.FillID == "" {
+			if n := seen[base]; n > 0 {
+				e.Fingerprint = hashParts("repeat", base, strconv.Itoa(n))
+			}
+			seen[base]++
+		}
+	}
+}
+
+// SplitFingerprint derives the fingerprint of one part of an execution that was
+// split in two, so each part stays unique and re-imports split the same way
+func SplitFingerprint(base, part string) string {
+	return hashParts("split", base, part)
+}
+
+// ComputeFingerprint returns a stable identity for the trade. Trades built from
+// executions are identified by their opening execution, so a position that gains
+// closing fills in a later import keeps the same fingerprint.
+func (t Trade) ComputeFingerprint() string {
+	if len(t.Executions) > 0 {
+		first := t.Executions[0]
+		for _, e := range t.Executions[1:] {
+			if e.ExecutedAt.Before(first.ExecutedAt) {
+				first = e
+			}
+		}
+
+		fingerprint := first.Fingerprint
+		if fingerprint == "" {
+			fingerprint = first.ComputeFingerprint()
+		}
+		return hashParts("trade", fingerprint)
+	}
+
+	return hashParts(
+		"trade",
+		strings.ToUpper(t.Symbol),
+		string(t.TradeType),
+		t.OpenedAt.UTC().Format(time.RFC3339Nano),
+		formatNumber(t.Quantity),
+		formatNumber(t.EntryPrice),
+	)
+}
+
+// ComputeFingerprint returns a stable identity for an imported ledger entry:
+// the broker's reference when there is one, otherwise a hash of its contents
+func (c CashTransaction) ComputeFingerprint() string {
+	if c.Reference != "" {
+		return hashParts("cash", c.Account, string(c.Type), c.Reference)
+	}
+
+	return hashParts(
+		"cash",
+		c.Account,
+		string(c.Type),
+		c.OccurredAt.UTC().Format(time.RFC3339Nano),
+		formatNumber(c.Amount),
+		c.Description,
+	)
+}
+
+func hashParts(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportSource string
+
+const (
+	ImportSourceCSV         ImportSource = "CSV"
+	ImportSourceDAS         ImportSource = "DAS"
+	ImportSourceIBKR        ImportSource = "IBKR"
+	ImportSourceProfile     ImportSource = "PROFILE"
+	ImportSourcePropReports ImportSource = "PROPREPORTS"
+	ImportSourceTOS         ImportSource = "THINKORSWIM"
+	ImportSourceNinjaTrader ImportSource = "NINJATRADER"
+	ImportSourceTradovate   ImportSource = "TRADOVATE"
+	ImportSourceMetaTrader  ImportSource = "METATRADER"
+	ImportSourceOFX         ImportSource = "OFX"
+)
+
+type ImportBatchStatus string
+
+const (
+	ImportBatchCommitted  ImportBatchStatus = "COMMITTED"
+	ImportBatchRolledBack ImportBatchStatus = "ROLLED_BACK"
+)
+
+// ImportBatch records one import run. Every trade and execution it created
+// carries its ID so the whole run can be rolled back at once.
+type ImportBatch struct {
+	ID             uuid.UUID         `json:"id"`
+	UserID         uuid.UUID         `json:"user_id"`
+	Source         ImportSource      `json:"source"`
+	FileName       string            `json:"file_name,omitempty"`
+	Status         ImportBatchStatus `json:"status"`
+	RowCount       int               `json:"row_count"`
+	InsertedCount  int               `json:"inserted_count"`
+	UpdatedCount   int               `json:"updated_count"`
+	DuplicateCount int               `json:"duplicate_count"`
+	ConflictCount  int               `json:"conflict_count"`
+	RolledBackAt   *time.Time        `json:"rolled_back_at,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fields an import profile can map a CSV column to
+const (
+	ImportFieldSymbol      = "symbol"
+	ImportFieldSide        = "side"
+	ImportFieldQuantity    = "quantity"
+	ImportFieldPrice       = "price"
+	ImportFieldDate        = "date"
+	ImportFieldTime        = "time"
+	ImportFieldAccount     = "account"
+	ImportFieldRoute       = "route"
+	ImportFieldLiquidity   = "liquidity"
+	ImportFieldOrderID     = "order_id"
+	ImportFieldFillID      = "fill_id"
+	ImportFieldCommission  = "commission"
+	ImportFieldECNFee      = "ecn_fee"
+	ImportFieldSECFee      = "sec_fee"
+	ImportFieldTAFFee      = "taf_fee"
+	ImportFieldNSCCFee     = "nscc_fee"
+	ImportFieldClearingFee = "clearing_fee"
+	ImportFieldMiscFee     = "misc_fee"
+)
+
+// ImportProfile describes how to read one broker's CSV export
+type ImportProfile struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	// ColumnMapping maps an import field (see ImportField*) to a CSV header name
+	ColumnMapping map[string]string `json:"column_mapping"`
+	// DateFormat and TimeFormat are Go reference layouts, e.g. "01/02/2006" and "15:04:05".
+	// When the date column holds both date and time, leave TimeFormat empty.
+	DateFormat string `json:"date_format,omitempty"`
+	TimeFormat string `json:"time_format,omitempty"`
+	// SideMapping adds broker-specific side values, e.g. {"BOT": "BUY", "SLD": "SELL"}
+	SideMapping map[string]ExecutionSide `json:"side_mapping,omitempty"`
+	Timezone    string                   `json:"timezone"`
+	Delimiter   string                   `json:"delimiter"`
+	// FeesAsDebits is set for brokers that write fees as negative amounts, so a
+	// positive amount is a rebate. Otherwise fees are positive and rebates negative.
+	FeesAsDebits bool      `json:"fees_as_debits"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IntegrationProvider string
+
+const (
+	ProviderPropReports IntegrationProvider = "PROPREPORTS"
+)
+
+type SyncStatus string
+
+const (
+	SyncRunning   SyncStatus = "RUNNING"
+	SyncSucceeded SyncStatus = "SUCCEEDED"
+	SyncFailed    SyncStatus = "FAILED"
+)
+
+// IntegrationConnection is a saved broker login. The credentials are stored
+// encrypted and never leave the server.
+type IntegrationConnection struct {
+	ID                   uuid.UUID           `json:"id"`
+	UserID               uuid.UUID           `json:"user_id"`
+	Provider             IntegrationProvider `json:"provider"`
+	Name                 string              `json:"name"`
+	Site                 string              `json:"site"`
+	Username             string              `json:"username"`
+	AccountID            string              `json:"account_id,omitempty"`
+	EncryptedCredentials []byte              `json:"-"`
+	LastSyncStatus       SyncStatus          `json:"last_sync_status,omitempty"`
+	LastSyncError        string              `json:"last_sync_error,omitempty"`
+	LastSyncAt           *time.Time          `json:"last_sync_at,omitempty"`
+	LastSyncJobID        *uuid.UUID          `json:"last_sync_job_id,omitempty"`
+	SyncEnabled          bool                `json:"sync_enabled"`
+	SyncWeekdays         []int               `json:"sync_weekdays"` // 0 = Sunday
+	SyncTime             string              `json:"sync_time"`     // HH:MM in SyncTimezone
+	SyncTimezone         string              `json:"sync_timezone"`
+	SyncedThrough        *time.Time          `json:"synced_through,omitempty"` // Last day fetched by a scheduled sync
+	SyncFailures         int                 `json:"sync_failures"`            // Consecutive failed scheduled syncs
+	NextSyncAt           *time.Time          `json:"next_sync_at,omitempty"`
+	CreatedAt            time.Time           `json:"created_at"`
+	UpdatedAt            time.Time           `json:"updated_at"`
+}
+
+// IntegrationCredentials is the secret part of a connection, encrypted as JSON
+type IntegrationCredentials struct {
+	Password string `json:"password"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobType string
+
+const (
+	JobTypeBrokerImport JobType = "BROKER_IMPORT"
+)
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "QUEUED"
+	JobRunning   JobStatus = "RUNNING"
+	JobCompleted JobStatus = "COMPLETED"
+	JobFailed    JobStatus = "FAILED"
+)
+
+// Job is a unit of background work such as a broker import
+type Job struct {
+	ID              uuid.UUID       `json:"id"`
+	UserID          uuid.UUID       `json:"user_id"`
+	Type            JobType         `json:"type"`
+	Status          JobStatus       `json:"status"`
+	Payload         json.RawMessage `json:"payload"`
+	Result          json.RawMessage `json:"result,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	ProgressCurrent int             `json:"progress_current"`
+	ProgressTotal   int             `json:"progress_total"`
+	ProgressMessage string          `json:"progress_message,omitempty"`
+	StartedAt       *time.Time      `json:"started_at,omitempty"`
+	FinishedAt      *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	// Secret holds credentials the job needs. It is kept in memory only and
+	// never written to the jobs table.
+	Secret json.RawMessage `json:"-"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderStatus string
+
+const (
+	OrderCanceled OrderStatus = "CANCELED"
+	OrderRejected OrderStatus = "REJECTED"
+)
+
+// OrderEvent is an order that never filled, kept alongside the trade it was part of
+type OrderEvent struct {
+	ID        uuid.UUID     `json:"id"`
+	TradeID   uuid.UUID     `json:"trade_id"`
+	UserID    uuid.UUID     `json:"user_id"`
+	Account   string        `json:"account,omitempty"`
+	Symbol    string        `json:"symbol"`
+	Side      ExecutionSide `json:"side"`
+	Quantity  float64       `json:"quantity"`
+	Price     float64       `json:"price"`
+	Route     string        `json:"route,omitempty"`
+	Status    OrderStatus   `json:"status"`
+	Note      string        `json:"note,omitempty"`
+	EventAt   time.Time     `json:"event_at"`
+	CreatedAt time.Time     `json:"created_at"`
+}
@@ -14,21 +14,53 @@ const (
 )
 
 type Trade struct {
-	ID         uuid.UUID  `json:"id"`
-	UserID     uuid.UUID  `json:"user_id"`
-	Symbol     string     `json:"symbol"`
-	TradeType  TradeType  `json:"trade_type"`
-	Quantity   float64    `json:"quantity"`
-	EntryPrice float64    `json:"entry_price"`
-	ExitPrice  *float64   `json:"exit_price,omitempty"`
-	Fees       float64    `json:"fees"`
-	PnL        *float64   `json:"pnl,omitempty"`
-	OpenedAt   time.Time  `json:"opened_at"`
-	ClosedAt   *time.Time `json:"closed_at,omitempty"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
-	HasJournal bool       `json:"has_journal,omitempty"`
-	Tags       []string   `json:"tags,omitempty"`
+	ID            uuid.UUID    `json:"id"`
+	UserID        uuid.UUID    `json:"user_id"`
+	Symbol        string       `json:"symbol"`
+	TradeType     TradeType    `json:"trade_type"`
+	Quantity      float64      `json:"quantity"`
+	EntryPrice    float64      `json:"entry_price"`
+	ExitPrice     *float64     `json:"exit_price,omitempty"`
+	StopLoss      *float64     `json:"stop_loss,omitempty"` // initial stop price; entry to stop is the risk (1R)
+	Fees          float64      `json:"fees"`
+	PnL           *float64     `json:"pnl,omitempty"`
+	Multiplier    float64      `json:"multiplier"`                // contract multiplier; 1 for shares
+	BrokerPnL     *float64     `json:"broker_pnl,omitempty"`      // realized P&L as reported by the broker
+	PnLFromBroker bool         `json:"pnl_from_broker,omitempty"` // P&L is BrokerPnL, as prices are in another currency
+	Currency      string       `json:"currency,omitempty"`        // ISO 4217 code of the account's currency
+	OpenedAt      time.Time    `json:"opened_at"`
+	ClosedAt      *time.Time   `json:"closed_at,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	HasJournal    bool         `json:"has_journal,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+	Fingerprint   string       `json:"fingerprint,omitempty"`
+	AccountID     *uuid.UUID   `json:"account_id,omitempty"`
+	ImportBatchID *uuid.UUID   `json:"import_batch_id,omitempty"`
+	Executions    []Execution  `json:"executions,omitempty"`
+	Orders        []OrderEvent `json:"orders,omitempty"`
+	// Continued marks a trade that carries on a position an earlier import left
+	// open, so an import applies its new fills to the stored trade
+	Continued bool `json:"-"`
+}
+
+// ContractMultiplier returns the trade's multiplier, treating an unset one as 1
+func (t Trade) ContractMultiplier() float64 {
+	if t.Multiplier == 0 {
+		return 1
+	}
+	return t.Multiplier
+}
+
+// DefaultCurrency is the currency of trades imported from accounts that do not state one
+const DefaultCurrency = "USD"
+
+// CurrencyCode returns the trade's currency, treating an unset one as DefaultCurrency
+func (t Trade) CurrencyCode() string {
+	if t.Currency == "" {
+		return DefaultCurrency
+	}
+	return t.Currency
 }
 
 type Tag struct {
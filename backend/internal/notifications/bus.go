@@ -13,15 +13,19 @@ import (
 type NotificationType string
 
 const (
-	NotificationTypeTradeCreated   NotificationType = "trade.created"
-	NotificationTypeTradeUpdated   NotificationType = "trade.updated"
-	NotificationTypeTradeDeleted   NotificationType = "trade.deleted"
-	NotificationTypeJournalCreated NotificationType = "journal.created"
-	NotificationTypeJournalUpdated NotificationType = "journal.updated"
-	NotificationTypeCSVImport      NotificationType = "csv.import"
-	NotificationTypeError          NotificationType = "error"
-	NotificationTypeInfo           NotificationType = "info"
-	NotificationTypeSuccess        NotificationType = "success"
+	NotificationTypeTradeCreated    NotificationType = "trade.created"
+	NotificationTypeTradeUpdated    NotificationType = "trade.updated"
+	NotificationTypeTradeDeleted    NotificationType = "trade.deleted"
+	NotificationTypeJournalCreated  NotificationType = "journal.created"
+	NotificationTypeJournalUpdated  NotificationType = "journal.updated"
+	NotificationTypeCSVImport       NotificationType = "csv.import"
+	NotificationTypeImportRollback  NotificationType = "import.rollback"
+	NotificationTypeImportProgress  NotificationType = "import.progress"
+	NotificationTypeImportCompleted NotificationType = "import.completed"
+	NotificationTypeImportFailed    NotificationType = "import.failed"
+	NotificationTypeError           NotificationType = "error"
+	NotificationTypeInfo            NotificationType = "info"
+	NotificationTypeSuccess         NotificationType = "success"
 )
 
 // Notification represents a notification message
@@ -0,0 +1,236 @@
+package positions
+
+import (
+	"math"
+	"sort"
+
+	"github.com/tradepulse/api/internal/models"
+)
+
+// epsilon absorbs float rounding when comparing share quantities
+const epsilon = 1e-9
+
+type positionKey struct {
+	Account string
+	Symbol  string
+}
+
+type position struct {
+	trade *models.Trade
+	net   float64 // signed: positive when long, negative when short
+}
+
+// Engine walks executions in time order and groups them into round-trip trades.
+// A trade starts when the position leaves flat and ends when it returns to flat.
+// A fill that takes the position through zero is split in two: the first part
+// closes the current trade and the remainder opens the next one.
+type Engine struct {
+	open   map[positionKey]*position
+	order  []positionKey
+	closed []models.Trade
+	seeded map[string]bool // fingerprints of executions restored by Seed
+}
+
+// NewEngine creates an engine with no open positions
+func NewEngine() *Engine {
+	return &Engine{
+		open:   make(map[positionKey]*position),
+		seeded: make(map[string]bool),
+	}
+}
+
+// Seed restores positions that were still open at the end of a previous import,
+// so that the closing fills of an overnight hold are attached to the same trade.
+// Seeded trades keep their ID and are marked Continued; callers use these to
+// update rather than insert them.
+// Executions already held by a seeded trade are skipped if they are processed
+// again, so an import may overlap the one that left the position open.
+func (e *Engine) Seed(trades []models.Trade) {
+	for _, trade := range trades {
+		if len(trade.Executions) == 0 {
+			continue
+		}
+
+		t := trade
+		t.Executions = append([]models.Execution(nil), trade.Executions...)
+		t.Continued = true
+
+		var net float64
+		for _, execution := range t.Executions {
+			net += signedQuantity(execution)
+		}
+		if math.Abs(net) < epsilon {
+			continue
+		}
+
+		key := positionKey{Account: t.Executions[0].Account, Symbol: t.Symbol}
+		if _, exists := e.open[key]; !exists {
+			e.order = append(e.order, key)
+		}
+		e.open[key] = &position{trade: &t, net: net}
+
+		for _, execution := range t.Executions {
+			e.seeded[fingerprint(execution)] = true
+		}
+	}
+}
+
+// Process applies executions to the tracked positions in time order.
+// Executions with the same timestamp keep the order they were given in, which
+// is also the order identical fills are fingerprinted in.
+func (e *Engine) Process(executions []models.Execution) {
+	sorted := append([]models.Execution(nil), executions...)
+	models.FingerprintExecutions(sorted)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ExecutedAt.Before(sorted[j].ExecutedAt)
+	})
+
+	for _, execution := range sorted {
+		e.apply(execution)
+	}
+}
+
+// Closed returns the round trips that returned to flat, in the order they closed
+func (e *Engine) Closed() []models.Trade {
+	return e.closed
+}
+
+// Open returns the positions that are still open. They can be passed to Seed
+// on the next import to carry them overnight.
+func (e *Engine) Open() []models.Trade {
+	trades := make([]models.Trade, 0, len(e.open))
+	for _, key := range e.order {
+		if pos, ok := e.open[key]; ok {
+			pos.trade.RebuildFromExecutions()
+			trades = append(trades, *pos.trade)
+		}
+	}
+	return trades
+}
+
+// Trades returns the closed round trips followed by the open positions
+func (e *Engine) Trades() []models.Trade {
+	trades := make([]models.Trade, 0, len(e.closed)+len(e.open))
+	trades = append(trades, e.closed...)
+	return append(trades, e.Open()...)
+}
+
+func (e *Engine) apply(execution models.Execution) {
+	if execution.Quantity <= 0 {
+		return
+	}
+
+	// A seeded trade may start with the opening part of a split fill, in which
+	// case the closing part already belongs to an earlier trade
+	fp := fingerprint(execution)
+	if e.seeded[fp] || e.seeded[models.SplitFingerprint(fp, "open")] {
+		return
+	}
+
+	key := positionKey{Account: execution.Account, Symbol: execution.Symbol}
+	pos, ok := e.open[key]
+	if !ok {
+		e.openPosition(key, execution)
+		return
+	}
+
+	signed := signedQuantity(execution)
+
+	// Adding to the position, or reducing it without reaching flat
+	if sameSign(pos.net, signed) || math.Abs(signed) < math.Abs(pos.net)-epsilon {
+		pos.trade.Executions = append(pos.trade.Executions, execution)
+		pos.net += signed
+		return
+	}
+
+	// Exactly flat: the fill closes the round trip
+	if math.Abs(math.Abs(signed)-math.Abs(pos.net)) < epsilon {
+		pos.trade.Executions = append(pos.trade.Executions, execution)
+		e.closePosition(key)
+		return
+	}
+
+	// The fill flips the position: close with part of it, open with the rest
+	closing, opening := splitExecution(execution, math.Abs(pos.net))
+	pos.trade.Executions = append(pos.trade.Executions, closing)
+	e.closePosition(key)
+	e.openPosition(key, opening)
+}
+
+func (e *Engine) openPosition(key positionKey, execution models.Execution) {
+	if _, exists := e.open[key]; !exists {
+		e.order = append(e.order, key)
+	}
+	e.open[key] = &position{
+		trade: &models.Trade{Executions: []models.Execution{execution}},
+		net:   signedQuantity(execution),
+	}
+}
+
+func (e *Engine) closePosition(key positionKey) {
+	pos := e.open[key]
+	pos.trade.RebuildFromExecutions()
+	e.closed = append(e.closed, *pos.trade)
+
+	delete(e.open, key)
+	for i, k := range e.order {
+		if k == key {
+			e.order = append(e.order[:i], e.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// splitExecution divides an execution into two parts, the first with the given
+// quantity. Fees are allocated to each part in proportion to its quantity.
+func splitExecution(execution models.Execution, quantity float64) (models.Execution, models.Execution) {
+	ratio := quantity / execution.Quantity
+
+	first := execution
+	first.Quantity = quantity
+	first.Commission = execution.Commission * ratio
+	first.ECNFee = execution.ECNFee * ratio
+	first.SECFee = execution.SECFee * ratio
+	first.TAFFee = execution.TAFFee * ratio
+	first.NSCCFee = execution.NSCCFee * ratio
+	first.ClearingFee = execution.ClearingFee * ratio
+	first.MiscFee = execution.MiscFee * ratio
+
+	rest := execution
+	rest.Quantity = execution.Quantity - quantity
+	rest.Commission = execution.Commission - first.Commission
+	rest.ECNFee = execution.ECNFee - first.ECNFee
+	rest.SECFee = execution.SECFee - first.SECFee
+	rest.TAFFee = execution.TAFFee - first.TAFFee
+	rest.NSCCFee = execution.NSCCFee - first.NSCCFee
+	rest.ClearingFee = execution.ClearingFee - first.ClearingFee
+	rest.MiscFee = execution.MiscFee - first.MiscFee
+
+	// Realized P&L reported by the broker belongs to the closing part
+	rest.RealizedPnL = nil
+
+	// Both parts come from the same broker fill, so each needs its own identity
+	base := fingerprint(execution)
+	first.Fingerprint = models.SplitFingerprint(base, "close")
+	rest.Fingerprint = models.SplitFingerprint(base, "open")
+
+	return first, rest
+}
+
+func fingerprint(execution models.Execution) string {
+	if execution.Fingerprint != "" {
+		return execution.Fingerprint
+	}
+	return execution.ComputeFingerprint()
+}
+
+func signedQuantity(execution models.Execution) float64 {
+	if execution.Side == models.SideBuy {
+		return execution.Quantity
+	}
+	return -execution.Quantity
+}
+
+func sameSign(a, b float64) bool {
+	return (a > 0 && b > 0) || (a < 0 && b < 0)
+}
@@ -0,0 +1,197 @@
+package positions
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/models"
+)
+
+var testStart = time.Date(2025, 11, 10, 14, 30, 0, 0, time.UTC)
+
+// fill builds an execution on account ACC1, minutes after testStart
+func fill(symbol string, side models.ExecutionSide, quantity, price float64, minutes int, fillID string) models.Execution {
+	return models.Execution{
+		Account:    "ACC1",
+		Symbol:     symbol,
+		Side:       side,
+		Quantity:   quantity,
+		Price:      price,
+		ExecutedAt: testStart.Add(time.Duration(minutes) * time.Minute),
+		FillID:     fillID,
+		Commission: quantity / 100,
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEngineRoundTrips(t *testing.T) {
+	engine := NewEngine()
+	engine.Process([]models.Execution{
+		fill("AAPL", models.SideBuy, 100, 10, 0, "1"),
+		fill("AAPL", models.SideBuy, 100, 11, 1, "2"),
+		fill("MSFT", models.SideSell, 50, 20, 2, "3"),
+		fill("AAPL", models.SideSell, 150, 12, 3, "4"),
+		fill("AAPL", models.SideSell, 50, 13, 4, "5"),
+	})
+
+	closed := engine.Closed()
+	if len(closed) != 1 {
+		t.Fatalf("closed trades = %d, want 1", len(closed))
+	}
+	trade := closed[0]
+	if trade.Continued {
+		t.Error("a trade opened by the processed fills is marked as continued")
+	}
+	if trade.Symbol != "AAPL" || trade.TradeType != models.TradeLong || trade.Quantity != 200 || len(trade.Executions) != 4 {
+		t.Errorf("closed trade = %s %s %v shares in %d fills, want AAPL LONG 200 in 4", trade.Symbol, trade.TradeType, trade.Quantity, len(trade.Executions))
+	}
+	if !approx(trade.EntryPrice, 10.5) || trade.ExitPrice == nil || !approx(*trade.ExitPrice, 12.25) {
+		t.Errorf("entry, exit = %v, %v; want 10.5, 12.25", trade.EntryPrice, trade.ExitPrice)
+	}
+	if trade.PnL == nil || !approx(*trade.PnL, 350-4) {
+		t.Errorf("pnl = %v, want 346", trade.PnL)
+	}
+
+	open := engine.Open()
+	if len(open) != 1 || open[0].Symbol != "MSFT" || open[0].TradeType != models.TradeShort || open[0].ClosedAt != nil {
+		t.Errorf("open trades = %+v, want one open MSFT short", open)
+	}
+	if trades := engine.Trades(); len(trades) != 2 || trades[0].Symbol != "AAPL" || trades[1].Symbol != "MSFT" {
+		t.Errorf("trades = %d, want the closed AAPL trade then the open MSFT one", len(trades))
+	}
+}
+
+func TestEngineFlipSplitsFill(t *testing.T) {
+	engine := NewEngine()
+	engine.Process([]models.Execution{
+		fill("TSLA", models.SideBuy, 100, 200, 0, "1"),
+		fill("TSLA", models.SideSell, 150, 210, 1, "2"),
+	})
+
+	closed, open := engine.Closed(), engine.Open()
+	if len(closed) != 1 || len(open) != 1 {
+		t.Fatalf("closed, open = %d, %d; want 1, 1", len(closed), len(open))
+	}
+
+	closing := closed[0].Executions[1]
+	opening := open[0].Executions[0]
+	if closed[0].TradeType != models.TradeLong || closing.Quantity != 100 || closed[0].PnL == nil || !approx(*closed[0].PnL, 1000-2) {
+		t.Errorf("closed = %s, closing part %v shares, pnl %v; want LONG, 100 shares, 998", closed[0].TradeType, closing.Quantity, closed[0].PnL)
+	}
+	if open[0].TradeType != models.TradeShort || opening.Quantity != 50 {
+		t.Errorf("open = %s with %v shares, want SHORT with 50", open[0].TradeType, opening.Quantity)
+	}
+
+	// The fill's commission is shared in proportion to quantity
+	if !approx(closing.Commission, 1) || !approx(opening.Commission, 0.5) {
+		t.Errorf("commission split = %v + %v, want 1 + 0.5", closing.Commission, opening.Commission)
+	}
+
+	base := fill("TSLA", models.SideSell, 150, 210, 1, "2").ComputeFingerprint()
+	if closing.Fingerprint != models.SplitFingerprint(base, "close") || opening.Fingerprint != models.SplitFingerprint(base, "open") {
+		t.Error("split parts do not carry the split fingerprints of the original fill")
+	}
+}
+
+func TestEngineKeepsIdenticalFills(t *testing.T) {
+	// Two partial fills of the same size and price in the same second, without fill ids
+	engine := NewEngine()
+	engine.Process([]models.Execution{
+		fill("AMD", models.SideBuy, 100, 150, 0, ""),
+		fill("AMD", models.SideBuy, 100, 150, 0, ""),
+		fill("AMD", models.SideSell, 200, 151, 5, ""),
+	})
+
+	closed := engine.Closed()
+	if len(closed) != 1 || closed[0].Quantity != 200 || len(closed[0].Executions) != 3 {
+		t.Fatalf("closed = %+v, want one 200 share trade with 3 fills", closed)
+	}
+	if closed[0].Executions[0].Fingerprint == closed[0].Executions[1].Fingerprint {
+		t.Error("identical fills share a fingerprint")
+	}
+}
+
+func TestEngineSeedCarriesPositionOvernight(t *testing.T) {
+	// Yesterday's import left 100 shares long open
+	first := NewEngine()
+	first.Process([]models.Execution{fill("NVDA", models.SideBuy, 100, 100, 0, "1")})
+	seed := first.Open()
+	seed[0].ID = uuid.New()
+
+	overnight := testStart.Add(24 * time.Hour)
+	closing := fill("NVDA", models.SideSell, 100, 105, 0, "2")
+	closing.ExecutedAt = overnight
+
+	engine := NewEngine()
+	engine.Seed(seed)
+	engine.Process([]models.Execution{
+		// An overlapping import repeats the opening fill, which must not be counted twice
+		fill("NVDA", models.SideBuy, 100, 100, 0, "1"),
+		closing,
+	})
+
+	closed := engine.Closed()
+	if len(closed) != 1 || len(engine.Open()) != 0 {
+		t.Fatalf("closed, open = %d, %d; want 1, 0", len(closed), len(engine.Open()))
+	}
+	trade := closed[0]
+	if trade.ID != seed[0].ID || !trade.Continued {
+		t.Errorf("closed trade ID = %s (continued %v), want the seeded %s continued", trade.ID, trade.Continued, seed[0].ID)
+	}
+	if trade.TradeType != models.TradeLong || trade.Quantity != 100 || len(trade.Executions) != 2 {
+		t.Errorf("closed trade = %s %v shares in %d fills, want LONG 100 in 2", trade.TradeType, trade.Quantity, len(trade.Executions))
+	}
+	if trade.ClosedAt == nil || !trade.ClosedAt.Equal(overnight) {
+		t.Errorf("closed at = %v, want %v", trade.ClosedAt, overnight)
+	}
+}
+
+func TestEngineSeedFromSplitFill(t *testing.T) {
+	flip := fill("SPY", models.SideSell, 150, 500, 1, "2")
+
+	// Yesterday's import closed a long with part of the flip and left the rest short
+	first := NewEngine()
+	first.Process([]models.Execution{fill("SPY", models.SideBuy, 100, 499, 0, "1"), flip})
+	seed := first.Open()
+	seed[0].ID = uuid.New()
+
+	engine := NewEngine()
+	engine.Seed(seed)
+	cover := fill("SPY", models.SideBuy, 50, 498, 60, "3")
+	engine.Process([]models.Execution{flip, cover})
+
+	closed := engine.Closed()
+	if len(closed) != 1 || closed[0].ID != seed[0].ID {
+		t.Fatalf("closed = %d trades, want the seeded short closed", len(closed))
+	}
+	if closed[0].TradeType != models.TradeShort || closed[0].Quantity != 50 || len(closed[0].Executions) != 2 {
+		t.Errorf("closed trade = %s %v shares in %d fills, want SHORT 50 in 2",
+			closed[0].TradeType, closed[0].Quantity, len(closed[0].Executions))
+	}
+	if len(engine.Open()) != 0 {
+		t.Errorf("open trades = %d, want 0: the repeated flip must not reopen a position", len(engine.Open()))
+	}
+}
+
+func TestEngineSeedSkipsFlatTrades(t *testing.T) {
+	flat := models.Trade{
+		ID:     uuid.New(),
+		Symbol: "QQQ",
+		Executions: []models.Execution{
+			fill("QQQ", models.SideBuy, 10, 400, 0, "1"),
+			fill("QQQ", models.SideSell, 10, 401, 1, "2"),
+		},
+	}
+
+	engine := NewEngine()
+	engine.Seed([]models.Trade{flat, {ID: uuid.New(), Symbol: "IWM"}})
+
+	if open := engine.Open(); len(open) != 0 {
+		t.Errorf("open trades = %d, want 0 for seeds that are flat or have no executions", len(open))
+	}
+}
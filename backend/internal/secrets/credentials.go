@@ -0,0 +1,32 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/tradepulse/api/internal/models"
+)
+
+// SealCredentials encrypts a connection's credentials, bound to the owning user
+func (c *Cipher) SealCredentials(userID uuid.UUID, credentials models.IntegrationCredentials) ([]byte, error) {
+	plaintext, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode credentials: %w", err)
+	}
+	return c.Encrypt(plaintext, userID[:])
+}
+
+// OpenCredentials decrypts a connection's stored credentials
+func (c *Cipher) OpenCredentials(connection *models.IntegrationConnection) (*models.IntegrationCredentials, error) {
+	plaintext, err := c.Decrypt(connection.EncryptedCredentials, connection.UserID[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt connection credentials: %w", err)
+	}
+
+	var credentials models.IntegrationCredentials
+	if err := json.Unmarshal(plaintext, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to decode connection credentials: %w", err)
+	}
+	return &credentials, nil
+}
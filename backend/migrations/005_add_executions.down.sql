@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_executions_symbol_executed_at;
+DROP INDEX IF EXISTS idx_executions_user_id;
+DROP INDEX IF EXISTS idx_executions_trade_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS executions;
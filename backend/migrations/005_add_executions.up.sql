@@ -0,0 +1,29 @@
+-- Executions (individual fills) that make up a trade
+CREATE TABLE IF NOT EXISTS executions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trade_id UUID NOT NULL REFERENCES trades(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    account VARCHAR(50) NOT NULL DEFAULT '',
+    symbol VARCHAR(20) NOT NULL,
+    side VARCHAR(4) NOT NULL CHECK (side IN ('BUY', 'SELL')),
+    quantity DECIMAL(18, 8) NOT NULL,
+    price DECIMAL(18, 8) NOT NULL,
+    executed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    route VARCHAR(20) NOT NULL DEFAULT '',
+    liquidity VARCHAR(10) NOT NULL DEFAULT '',
+    order_id VARCHAR(64) NOT NULL DEFAULT '',
+    fill_id VARCHAR(64) NOT NULL DEFAULT '',
+    commission DECIMAL(18, 8) NOT NULL DEFAULT 0,
+    ecn_fee DECIMAL(18, 8) NOT NULL DEFAULT 0,
+    sec_fee DECIMAL(18, 8) NOT NULL DEFAULT 0,
+    taf_fee DECIMAL(18, 8) NOT NULL DEFAULT 0,
+    nscc_fee DECIMAL(18, 8) NOT NULL DEFAULT 0,
+    clearing_fee DECIMAL(18, 8) NOT NULL DEFAULT 0,
+    misc_fee DECIMAL(18, 8) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_executions_trade_id ON executions(trade_id);
+CREATE INDEX IF NOT EXISTS idx_executions_user_id ON executions(user_id);
+CREATE INDEX IF NOT EXISTS idx_executions_symbol_executed_at ON executions(symbol, executed_at);
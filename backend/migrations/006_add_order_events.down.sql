@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_order_events_user_id;
+DROP INDEX IF EXISTS idx_order_events_trade_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS order_events;
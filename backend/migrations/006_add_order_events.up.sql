@@ -0,0 +1,20 @@
+-- Canceled and rejected orders, linked to the trade they were part of
+CREATE TABLE IF NOT EXISTS order_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trade_id UUID NOT NULL REFERENCES trades(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    account VARCHAR(50) NOT NULL DEFAULT '',
+    symbol VARCHAR(20) NOT NULL,
+    side VARCHAR(4) NOT NULL CHECK (side IN ('BUY', 'SELL')),
+    quantity DECIMAL(18, 8) NOT NULL,
+    price DECIMAL(18, 8) NOT NULL,
+    route VARCHAR(20) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('CANCELED', 'REJECTED')),
+    note TEXT NOT NULL DEFAULT '',
+    event_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_order_events_trade_id ON order_events(trade_id);
+CREATE INDEX IF NOT EXISTS idx_order_events_user_id ON order_events(user_id);
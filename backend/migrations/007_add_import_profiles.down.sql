@@ -0,0 +1,8 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_import_profiles_updated_at ON import_profiles;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_import_profiles_user_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS import_profiles;
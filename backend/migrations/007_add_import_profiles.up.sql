@@ -0,0 +1,23 @@
+-- Saved column mappings for broker CSV formats
+CREATE TABLE IF NOT EXISTS import_profiles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    column_mapping JSONB NOT NULL DEFAULT '{}'::jsonb,
+    date_format VARCHAR(50) NOT NULL DEFAULT '',
+    time_format VARCHAR(50) NOT NULL DEFAULT '',
+    side_mapping JSONB NOT NULL DEFAULT '{}'::jsonb,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'America/New_York',
+    delimiter VARCHAR(5) NOT NULL DEFAULT ',',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(user_id, name)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_import_profiles_user_id ON import_profiles(user_id);
+
+-- Create updated_at trigger for import_profiles
+DROP TRIGGER IF EXISTS update_import_profiles_updated_at ON import_profiles;
+CREATE TRIGGER update_import_profiles_updated_at BEFORE UPDATE ON import_profiles
+    FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
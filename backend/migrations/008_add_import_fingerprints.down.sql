@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_executions_user_fingerprint;
+DROP INDEX IF EXISTS idx_trades_user_fingerprint;
+
+-- Remove fingerprint columns
+ALTER TABLE executions DROP COLUMN IF EXISTS fingerprint;
+ALTER TABLE trades DROP COLUMN IF EXISTS fingerprint;
@@ -0,0 +1,9 @@
+-- Stable identities for imported trades and executions so re-imports can be detected
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS fingerprint VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS fingerprint VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Manually entered trades have no fingerprint and are not constrained
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trades_user_fingerprint
+    ON trades(user_id, fingerprint) WHERE fingerprint <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_executions_user_fingerprint
+    ON executions(user_id, fingerprint) WHERE fingerprint <> '';
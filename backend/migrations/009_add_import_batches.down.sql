@@ -0,0 +1,14 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_import_batches_updated_at ON import_batches;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_executions_import_batch_id;
+DROP INDEX IF EXISTS idx_trades_import_batch_id;
+DROP INDEX IF EXISTS idx_import_batches_user_id;
+
+-- Remove batch references
+ALTER TABLE executions DROP COLUMN IF EXISTS import_batch_id;
+ALTER TABLE trades DROP COLUMN IF EXISTS import_batch_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS import_batches;
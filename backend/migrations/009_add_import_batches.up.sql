@@ -0,0 +1,30 @@
+-- One row per import run so a bad import can be reviewed and rolled back as a whole
+CREATE TABLE IF NOT EXISTS import_batches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source VARCHAR(50) NOT NULL,
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'COMMITTED' CHECK (status IN ('COMMITTED', 'ROLLED_BACK')),
+    row_count INTEGER NOT NULL DEFAULT 0,
+    inserted_count INTEGER NOT NULL DEFAULT 0,
+    updated_count INTEGER NOT NULL DEFAULT 0,
+    duplicate_count INTEGER NOT NULL DEFAULT 0,
+    conflict_count INTEGER NOT NULL DEFAULT 0,
+    rolled_back_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Tag trades and executions with the batch that created them
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS import_batch_id UUID REFERENCES import_batches(id) ON DELETE SET NULL;
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS import_batch_id UUID REFERENCES import_batches(id) ON DELETE SET NULL;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_import_batches_user_id ON import_batches(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_trades_import_batch_id ON trades(import_batch_id);
+CREATE INDEX IF NOT EXISTS idx_executions_import_batch_id ON executions(import_batch_id);
+
+-- Create updated_at trigger for import_batches
+DROP TRIGGER IF EXISTS update_import_batches_updated_at ON import_batches;
+CREATE TRIGGER update_import_batches_updated_at BEFORE UPDATE ON import_batches
+    FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
@@ -0,0 +1,9 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_jobs_updated_at ON jobs;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_jobs_queued;
+DROP INDEX IF EXISTS idx_jobs_user_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS jobs;
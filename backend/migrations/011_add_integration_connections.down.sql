@@ -0,0 +1,8 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_integration_connections_updated_at ON integration_connections;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_integration_connections_user_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS integration_connections;
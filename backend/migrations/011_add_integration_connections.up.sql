@@ -0,0 +1,26 @@
+-- Saved broker connections; credentials are encrypted by the API with a server key
+CREATE TABLE IF NOT EXISTS integration_connections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    site VARCHAR(255) NOT NULL DEFAULT '',
+    username VARCHAR(255) NOT NULL DEFAULT '',
+    account_id VARCHAR(100) NOT NULL DEFAULT '',
+    encrypted_credentials BYTEA NOT NULL,
+    last_sync_status VARCHAR(20) NOT NULL DEFAULT '' CHECK (last_sync_status IN ('', 'RUNNING', 'SUCCEEDED', 'FAILED')),
+    last_sync_error TEXT NOT NULL DEFAULT '',
+    last_sync_at TIMESTAMP WITH TIME ZONE,
+    last_sync_job_id UUID REFERENCES jobs(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(user_id, name)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_integration_connections_user_id ON integration_connections(user_id);
+
+-- Create updated_at trigger for integration_connections
+DROP TRIGGER IF EXISTS update_integration_connections_updated_at ON integration_connections;
+CREATE TRIGGER update_integration_connections_updated_at BEFORE UPDATE ON integration_connections
+    FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
@@ -0,0 +1,11 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_integration_connections_next_sync;
+
+-- Remove sync schedule columns
+ALTER TABLE integration_connections DROP COLUMN IF EXISTS next_sync_at;
+ALTER TABLE integration_connections DROP COLUMN IF EXISTS sync_failures;
+ALTER TABLE integration_connections DROP COLUMN IF EXISTS synced_through;
+ALTER TABLE integration_connections DROP COLUMN IF EXISTS sync_timezone;
+ALTER TABLE integration_connections DROP COLUMN IF EXISTS sync_time;
+ALTER TABLE integration_connections DROP COLUMN IF EXISTS sync_weekdays;
+ALTER TABLE integration_connections DROP COLUMN IF EXISTS sync_enabled;
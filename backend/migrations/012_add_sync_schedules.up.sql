@@ -0,0 +1,12 @@
+-- Automatic sync schedule for saved broker connections. synced_through is the
+-- last trading day already fetched; failed runs retry with backoff and leave it unchanged.
+ALTER TABLE integration_connections ADD COLUMN IF NOT EXISTS sync_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE integration_connections ADD COLUMN IF NOT EXISTS sync_weekdays INTEGER[] NOT NULL DEFAULT '{1,2,3,4,5}';
+ALTER TABLE integration_connections ADD COLUMN IF NOT EXISTS sync_time VARCHAR(5) NOT NULL DEFAULT '16:30';
+ALTER TABLE integration_connections ADD COLUMN IF NOT EXISTS sync_timezone VARCHAR(100) NOT NULL DEFAULT 'America/New_York';
+ALTER TABLE integration_connections ADD COLUMN IF NOT EXISTS synced_through DATE;
+ALTER TABLE integration_connections ADD COLUMN IF NOT EXISTS sync_failures INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE integration_connections ADD COLUMN IF NOT EXISTS next_sync_at TIMESTAMP WITH TIME ZONE;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_integration_connections_next_sync ON integration_connections(next_sync_at) WHERE sync_enabled;
@@ -0,0 +1,23 @@
+-- Restore the original P&L calculation
+CREATE OR REPLACE FUNCTION calculate_pnl()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.exit_price IS NOT NULL THEN
+        IF NEW.trade_type = 'LONG' THEN
+            NEW.pnl = (NEW.exit_price - NEW.entry_price) * NEW.quantity - NEW.fees;
+        ELSE
+            NEW.pnl = (NEW.entry_price - NEW.exit_price) * NEW.quantity - NEW.fees;
+        END IF;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+-- The widened symbol columns are left in place: narrowing them back to
+-- VARCHAR(20) would fail once option or futures symbols longer than that are stored
+
+-- Remove multiplier and broker P&L columns
+ALTER TABLE executions DROP COLUMN IF EXISTS realized_pnl;
+ALTER TABLE executions DROP COLUMN IF EXISTS multiplier;
+ALTER TABLE trades DROP COLUMN IF EXISTS broker_pnl;
+ALTER TABLE trades DROP COLUMN IF EXISTS multiplier;
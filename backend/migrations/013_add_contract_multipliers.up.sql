@@ -0,0 +1,25 @@
+-- Contract multipliers for options and futures, and the realized P&L reported by
+-- the broker so it can be reconciled against the P&L calculated here
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS multiplier DECIMAL(18, 8) NOT NULL DEFAULT 1;
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS broker_pnl DECIMAL(18, 8);
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS multiplier DECIMAL(18, 8) NOT NULL DEFAULT 1;
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS realized_pnl DECIMAL(18, 8);
+
+-- OCC option symbols do not fit in 20 characters
+ALTER TABLE trades ALTER COLUMN symbol TYPE VARCHAR(32);
+ALTER TABLE executions ALTER COLUMN symbol TYPE VARCHAR(32);
+
+-- Function to calculate P&L for trades, scaled by the contract multiplier
+CREATE OR REPLACE FUNCTION calculate_pnl()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.exit_price IS NOT NULL THEN
+        IF NEW.trade_type = 'LONG' THEN
+            NEW.pnl = (NEW.exit_price - NEW.entry_price) * NEW.quantity * NEW.multiplier - NEW.fees;
+        ELSE
+            NEW.pnl = (NEW.entry_price - NEW.exit_price) * NEW.quantity * NEW.multiplier - NEW.fees;
+        END IF;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
@@ -0,0 +1,2 @@
+-- Remove currency column
+ALTER TABLE trades DROP COLUMN IF EXISTS currency;
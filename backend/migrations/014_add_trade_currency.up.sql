@@ -0,0 +1,3 @@
+-- Currency that a trade's prices and P&L are stated in, taken from the account
+-- it was traded in
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'USD';
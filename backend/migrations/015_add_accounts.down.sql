@@ -0,0 +1,13 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_accounts_updated_at ON accounts;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_trades_account_id;
+DROP INDEX IF EXISTS idx_accounts_user_number;
+DROP INDEX IF EXISTS idx_accounts_user_id;
+
+-- Remove account references
+ALTER TABLE trades DROP COLUMN IF EXISTS account_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS accounts;
@@ -0,0 +1,27 @@
+-- Trading accounts that trades are booked to
+CREATE TABLE IF NOT EXISTS accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    broker VARCHAR(100) NOT NULL DEFAULT '',
+    account_number VARCHAR(100) NOT NULL DEFAULT '',
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    account_type VARCHAR(20) NOT NULL DEFAULT 'CASH' CHECK (account_type IN ('CASH', 'MARGIN', 'IRA', 'PROP', 'OTHER')),
+    starting_balance DECIMAL(18, 8) NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Imports match trades to accounts by the account number the broker reports
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS account_id UUID REFERENCES accounts(id) ON DELETE SET NULL;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_accounts_user_id ON accounts(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_user_number ON accounts(user_id, account_number) WHERE account_number <> '';
+CREATE INDEX IF NOT EXISTS idx_trades_account_id ON trades(account_id);
+
+-- Create updated_at trigger for accounts
+DROP TRIGGER IF EXISTS update_accounts_updated_at ON accounts;
+CREATE TRIGGER update_accounts_updated_at BEFORE UPDATE ON accounts
+    FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
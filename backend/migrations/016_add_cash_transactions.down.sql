@@ -0,0 +1,11 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_cash_transactions_updated_at ON cash_transactions;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_cash_transactions_account_fingerprint;
+DROP INDEX IF EXISTS idx_cash_transactions_import_batch_id;
+DROP INDEX IF EXISTS idx_cash_transactions_account_occurred;
+DROP INDEX IF EXISTS idx_cash_transactions_user_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS cash_transactions;
@@ -0,0 +1,30 @@
+-- Cash movements on an account that are not trades: funding, fees, interest,
+-- dividends and prop firm payouts
+CREATE TABLE IF NOT EXISTS cash_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('DEPOSIT', 'WITHDRAWAL', 'FEE', 'INTEREST', 'DIVIDEND', 'PAYOUT', 'ADJUSTMENT', 'OTHER')),
+    amount DECIMAL(18, 8) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    fingerprint VARCHAR(64) NOT NULL DEFAULT '',
+    import_batch_id UUID REFERENCES import_batches(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_cash_transactions_user_id ON cash_transactions(user_id);
+CREATE INDEX IF NOT EXISTS idx_cash_transactions_account_occurred ON cash_transactions(account_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_cash_transactions_import_batch_id ON cash_transactions(import_batch_id);
+
+-- Manually entered transactions have no fingerprint and are not constrained
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cash_transactions_account_fingerprint
+    ON cash_transactions(account_id, fingerprint) WHERE fingerprint <> '';
+
+-- Create updated_at trigger for cash_transactions
+DROP TRIGGER IF EXISTS update_cash_transactions_updated_at ON cash_transactions;
+CREATE TRIGGER update_cash_transactions_updated_at BEFORE UPDATE ON cash_transactions
+    FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
@@ -0,0 +1,2 @@
+-- Remove stop loss column
+ALTER TABLE trades DROP COLUMN IF EXISTS stop_loss;
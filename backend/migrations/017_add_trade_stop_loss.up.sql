@@ -0,0 +1,3 @@
+-- Initial stop price of a trade. The distance from entry to stop sets the
+-- amount risked (1R), which results are measured against.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS stop_loss DECIMAL(18, 8);
@@ -0,0 +1,18 @@
+-- Restore the multiplier-scaled P&L calculation
+CREATE OR REPLACE FUNCTION calculate_pnl()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.exit_price IS NOT NULL THEN
+        IF NEW.trade_type = 'LONG' THEN
+            NEW.pnl = (NEW.exit_price - NEW.entry_price) * NEW.quantity * NEW.multiplier - NEW.fees;
+        ELSE
+            NEW.pnl = (NEW.entry_price - NEW.exit_price) * NEW.quantity * NEW.multiplier - NEW.fees;
+        END IF;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+-- Remove broker P&L source columns
+ALTER TABLE trades DROP COLUMN IF EXISTS pnl_from_broker;
+ALTER TABLE executions DROP COLUMN IF EXISTS foreign_quote;
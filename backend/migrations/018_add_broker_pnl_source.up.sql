@@ -0,0 +1,23 @@
+-- Fills priced in a currency other than the account's, such as USDJPY on a USD
+-- MetaTrader account. Price differences of their trades are not in the account
+-- currency, so the P&L of those trades is the one the broker reports.
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS foreign_quote BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS pnl_from_broker BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Function to calculate P&L for trades, scaled by the contract multiplier, or
+-- taken from the broker for trades priced in another currency
+CREATE OR REPLACE FUNCTION calculate_pnl()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.exit_price IS NOT NULL THEN
+        IF NEW.pnl_from_broker THEN
+            NEW.pnl = NEW.broker_pnl;
+        ELSIF NEW.trade_type = 'LONG' THEN
+            NEW.pnl = (NEW.exit_price - NEW.entry_price) * NEW.quantity * NEW.multiplier - NEW.fees;
+        ELSE
+            NEW.pnl = (NEW.entry_price - NEW.exit_price) * NEW.quantity * NEW.multiplier - NEW.fees;
+        END IF;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
@@ -0,0 +1,2 @@
+-- Remove account import batch column
+ALTER TABLE accounts DROP COLUMN IF EXISTS import_batch_id;
@@ -0,0 +1,3 @@
+-- Accounts created by an import for broker account numbers seen for the first
+-- time, so rolling the import back can remove them again
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS import_batch_id UUID REFERENCES import_batches(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+-- Remove profile fee sign column
+ALTER TABLE import_profiles DROP COLUMN IF EXISTS fees_as_debits;
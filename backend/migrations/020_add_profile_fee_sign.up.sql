@@ -0,0 +1,3 @@
+-- Whether a profile's broker writes fees as negative debits, so positive
+-- amounts are rebates
+ALTER TABLE import_profiles ADD COLUMN IF NOT EXISTS fees_as_debits BOOLEAN NOT NULL DEFAULT FALSE;